@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// amendmentLogFile is the append-only log of result amendments kept in the monitored directory.
+const amendmentLogFile = "polyfield-amendments.jsonl"
+
+// CompetitorChange records a single field that changed for one competitor between two saves.
+type CompetitorChange struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Field     string `json:"field"` // 'place', 'time', 'status', 'id', 'name' or 'affiliation'
+	Old       string `json:"old"`
+	New       string `json:"new"`
+}
+
+// ResultDiff describes what changed when a result file was re-saved.
+type ResultDiff struct {
+	FileName  string             `json:"fileName"`
+	EventName string             `json:"eventName"`
+	Time      int64              `json:"time"` // Unix time the amendment was detected
+	Added     []Competitor       `json:"added"`
+	Removed   []Competitor       `json:"removed"`
+	Changes   []CompetitorChange `json:"changes"`
+}
+
+// competitorStatus returns the result status of a competitor: 'DQ', 'DNF', 'DNS'
+// for the competitors exports add from LifData.DNS, or empty for a timed result.
+func competitorStatus(c Competitor) string {
+	switch c.Time {
+	case "DQ", "DNF", "DNS":
+		return c.Time
+	}
+	return ""
+}
+
+func competitorNameKey(c Competitor) string {
+	return strings.ToLower(strings.TrimSpace(c.FirstName) + "|" + strings.TrimSpace(c.LastName))
+}
+
+// diffLifData compares two parses of the same result file and returns the changes,
+// or nil if the competitors are identical. Competitors are matched by bib first and
+// then by name, so a corrected bib is reported as a change rather than a swap.
+func diffLifData(prev, next *LifData) *ResultDiff {
+	if prev == nil || next == nil {
+		return nil
+	}
+	diff := &ResultDiff{
+		FileName:  next.FileName,
+		EventName: next.EventName,
+		Time:      time.Now().Unix(),
+	}
+
+	matched := make([]bool, len(prev.Competitors))
+	pairs := make([]int, len(next.Competitors))
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	// First pass: match on bib.
+	for i, c := range next.Competitors {
+		if c.ID == "" {
+			continue
+		}
+		for j, p := range prev.Competitors {
+			if !matched[j] && p.ID == c.ID {
+				matched[j] = true
+				pairs[i] = j
+				break
+			}
+		}
+	}
+	// Second pass: match the remainder on name.
+	for i, c := range next.Competitors {
+		if pairs[i] != -1 || competitorNameKey(c) == "|" {
+			continue
+		}
+		for j, p := range prev.Competitors {
+			if !matched[j] && competitorNameKey(p) == competitorNameKey(c) {
+				matched[j] = true
+				pairs[i] = j
+				break
+			}
+		}
+	}
+
+	for i, c := range next.Competitors {
+		if pairs[i] == -1 {
+			diff.Added = append(diff.Added, c)
+			continue
+		}
+		p := prev.Competitors[pairs[i]]
+		change := func(field, oldVal, newVal string) {
+			if oldVal != newVal {
+				diff.Changes = append(diff.Changes, CompetitorChange{
+					ID:        c.ID,
+					FirstName: c.FirstName,
+					LastName:  c.LastName,
+					Field:     field,
+					Old:       oldVal,
+					New:       newVal,
+				})
+			}
+		}
+		change("id", p.ID, c.ID)
+		change("name", strings.TrimSpace(p.FirstName+" "+p.LastName), strings.TrimSpace(c.FirstName+" "+c.LastName))
+		change("affiliation", p.Affiliation, c.Affiliation)
+		change("place", p.Place, c.Place)
+		// A status change (e.g. a DQ added after review) replaces the time, so report it once.
+		if competitorStatus(p) != competitorStatus(c) {
+			change("status", competitorStatus(p), competitorStatus(c))
+		} else {
+			change("time", p.Time, c.Time)
+		}
+	}
+	for j, p := range prev.Competitors {
+		if !matched[j] {
+			diff.Removed = append(diff.Removed, p)
+		}
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changes) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// appendAmendmentLog appends a diff as a JSON line to the amendment log in dir.
+func appendAmendmentLog(dir string, diff *ResultDiff) error {
+	f, err := os.OpenFile(filepath.Join(dir, amendmentLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open amendment log: %v", err)
+	}
+	defer f.Close()
+	line, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("failed to encode amendment: %v", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write amendment log: %v", err)
+	}
+	return nil
+}
+
+// seedResults parses every result file in the monitored directory so that the
+// first re-save of an existing file can be compared against its previous contents.
+func (a *App) seedResults() {
+	entries, err := os.ReadDir(a.monitoredDir)
+	if err != nil {
+		resultsLog.Error("Failed to read directory for amendment tracking", "error", err)
+		return
+	}
+	seeded := make(map[string]*LifData)
+	hashes := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !isResultFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(a.monitoredDir, entry.Name())
+		data, err := a.parseResult(path)
+		a.auditResultFile(path, data, err)
+		if err != nil {
+			continue
+		}
+		seeded[data.FileName] = data
+		hashes[data.FileName], _ = fileHash(path)
+		a.metrics.recordResult(time.Unix(data.ModifiedTime, 0))
+	}
+	a.mu.Lock()
+	a.lastResults = seeded
+	a.amendments = nil
+	a.amended = make(map[string]bool)
+	hold := a.holdForApproval
+	a.mu.Unlock()
+	if hold {
+		a.reconcileApprovals(seeded, hashes)
+	}
+}
+
+// recordResult stores a freshly parsed result and, if the file had been seen
+// before, logs and broadcasts what changed. It returns the diff or nil.
+func (a *App) recordResult(data *LifData) *ResultDiff {
+	a.mu.Lock()
+	prev := a.lastResults[data.FileName]
+	a.lastResults[data.FileName] = data
+	a.mu.Unlock()
+
+	diff := diffLifData(prev, data)
+	if diff == nil {
+		return nil
+	}
+	a.mu.Lock()
+	a.amendments = append(a.amendments, diff)
+	a.amended[data.FileName] = true
+	a.mu.Unlock()
+
+	resultsLog.Info("Result amended", "file", data.FileName,
+		"added", len(diff.Added), "removed", len(diff.Removed), "changed", len(diff.Changes))
+	if err := appendAmendmentLog(a.monitoredDir, diff); err != nil {
+		resultsLog.Error("Failed to write amendment log", "error", err)
+	}
+	a.emitEvent("result-amended", diff)
+	return diff
+}
+
+// isAmended reports whether a result file has been amended since monitoring started.
+func (a *App) isAmended(fileName string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.amended[fileName]
+}
+
+// GetAmendments returns the amendments detected since monitoring started, newest first.
+// If fileName is non-empty only amendments to that file are returned.
+func (a *App) GetAmendments(fileName string) []*ResultDiff {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive != nil {
+		result := make([]*ResultDiff, 0, len(a.archive.amendments))
+		for _, diff := range a.archive.amendments {
+			if fileName == "" || diff.FileName == fileName {
+				result = append(result, diff)
+			}
+		}
+		return result
+	}
+	result := make([]*ResultDiff, 0, len(a.amendments))
+	for i := len(a.amendments) - 1; i >= 0; i-- {
+		if fileName == "" || a.amendments[i].FileName == fileName {
+			result = append(result, a.amendments[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLifData(t *testing.T) {
+	alice := Competitor{Place: "1", ID: "101", FirstName: "Alice", LastName: "Smith", Affiliation: "Kingston", Time: "12.01"}
+	bella := Competitor{Place: "2", ID: "102", FirstName: "Bella", LastName: "Jones", Affiliation: "Herne Hill", Time: "12.34"}
+	cara := Competitor{Place: "3", ID: "103", FirstName: "Cara", LastName: "Brown", Affiliation: "Sutton", Time: "12.90"}
+
+	with := func(c Competitor, edit func(*Competitor)) Competitor {
+		edit(&c)
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		prev    []Competitor
+		next    []Competitor
+		want    bool // whether a diff is expected
+		added   []Competitor
+		removed []Competitor
+		changes []CompetitorChange
+	}{
+		{
+			name: "identical",
+			prev: []Competitor{alice, bella},
+			next: []Competitor{alice, bella},
+		},
+		{
+			name: "time corrected",
+			prev: []Competitor{alice, bella},
+			next: []Competitor{alice, with(bella, func(c *Competitor) { c.Time = "12.35" })},
+			want: true,
+			changes: []CompetitorChange{
+				{ID: "102", FirstName: "Bella", LastName: "Jones", Field: "time", Old: "12.34", New: "12.35"},
+			},
+		},
+		{
+			name: "disqualified after review",
+			prev: []Competitor{alice, bella},
+			next: []Competitor{with(bella, func(c *Competitor) { c.Place = "1" }), with(alice, func(c *Competitor) { c.Place = ""; c.Time = "DQ" })},
+			want: true,
+			changes: []CompetitorChange{
+				{ID: "102", FirstName: "Bella", LastName: "Jones", Field: "place", Old: "2", New: "1"},
+				{ID: "101", FirstName: "Alice", LastName: "Smith", Field: "place", Old: "1", New: ""},
+				{ID: "101", FirstName: "Alice", LastName: "Smith", Field: "status", Old: "", New: "DQ"},
+			},
+		},
+		{
+			name: "bib corrected is matched by name",
+			prev: []Competitor{alice},
+			next: []Competitor{with(alice, func(c *Competitor) { c.ID = "111" })},
+			want: true,
+			changes: []CompetitorChange{
+				{ID: "111", FirstName: "Alice", LastName: "Smith", Field: "id", Old: "101", New: "111"},
+			},
+		},
+		{
+			name:    "competitor added and removed",
+			prev:    []Competitor{alice, bella},
+			next:    []Competitor{alice, cara},
+			want:    true,
+			added:   []Competitor{cara},
+			removed: []Competitor{bella},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := &LifData{FileName: "001-1-01.lif", EventName: "100m", Competitors: tt.prev}
+			next := &LifData{FileName: "001-1-01.lif", EventName: "100m", Competitors: tt.next}
+			diff := diffLifData(prev, next)
+			if !tt.want {
+				if diff != nil {
+					t.Fatalf("diffLifData() = %+v, want nil", diff)
+				}
+				return
+			}
+			if diff == nil {
+				t.Fatal("diffLifData() = nil, want a diff")
+			}
+			if !reflect.DeepEqual(diff.Added, tt.added) {
+				t.Errorf("Added = %+v, want %+v", diff.Added, tt.added)
+			}
+			if !reflect.DeepEqual(diff.Removed, tt.removed) {
+				t.Errorf("Removed = %+v, want %+v", diff.Removed, tt.removed)
+			}
+			if !reflect.DeepEqual(diff.Changes, tt.changes) {
+				t.Errorf("Changes = %+v, want %+v", diff.Changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestDiffLifDataNil(t *testing.T) {
+	data := &LifData{Competitors: []Competitor{{ID: "1"}}}
+	if diff := diffLifData(nil, data); diff != nil {
+		t.Errorf("diffLifData(nil, data) = %+v, want nil", diff)
+	}
+	if diff := diffLifData(data, nil); diff != nil {
+		t.Errorf("diffLifData(data, nil) = %+v, want nil", diff)
+	}
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// approvalsFile stores the approval queue in the monitored directory, so pending and
+// rejected results stay unpublished across restarts.
+const approvalsFile = "polyfield-approvals.json"
+
+// PendingResult is a parsed result file waiting for operator approval.
+type PendingResult struct {
+	ID         string      `json:"id"`
+	Data       *LifData    `json:"data"`
+	ReceivedAt int64       `json:"receivedAt"`
+	Diff       *ResultDiff `json:"diff"` // Changes against the previous save, if any
+	Hash       string      `json:"hash"` // SHA-256 of the file contents that were held
+}
+
+// approvalState is the approval queue as saved in approvalsFile.
+type approvalState struct {
+	Approved       map[string]*LifData `json:"approved"`       // file name -> approved result, including edits
+	ApprovedHashes map[string]string   `json:"approvedHashes"` // file name -> hash of the approved file contents
+	Pending        []*PendingResult    `json:"pending"`
+	PendingSeq     int                 `json:"pendingSeq"`
+	Rejected       map[string]string   `json:"rejected"` // file name -> hash of the rejected file contents
+}
+
+// loadApprovals reads the approvals file from dir. A missing file returns nil.
+func loadApprovals(dir string) (*approvalState, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, approvalsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state approvalState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", approvalsFile, err)
+	}
+	return &state, nil
+}
+
+// writeApprovals writes an encoded approvalState to the approvals file in dir.
+func writeApprovals(dir string, raw []byte) error {
+	path := filepath.Join(dir, approvalsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return fmt.Errorf("failed to save approvals: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// hashContents returns the hex SHA-256 of a result file's contents.
+func hashContents(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileHash returns the hex SHA-256 of the file at path.
+func fileHash(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return hashContents(raw), nil
+}
+
+// initApprovals loads the approval queue for the monitored directory.
+func (a *App) initApprovals() {
+	if a.monitoredDir == "" {
+		return
+	}
+	state, err := loadApprovals(a.monitoredDir)
+	if err != nil {
+		resultsLog.Error("Failed to load approvals", "error", err)
+	}
+	if state == nil {
+		state = &approvalState{}
+	}
+	if state.ApprovedHashes == nil {
+		state.ApprovedHashes = make(map[string]string)
+	}
+	if state.Rejected == nil {
+		state.Rejected = make(map[string]string)
+	}
+	a.mu.Lock()
+	a.approved = state.Approved
+	a.approvedHashes = state.ApprovedHashes
+	a.pending = state.Pending
+	a.pendingSeq = state.PendingSeq
+	a.rejected = state.Rejected
+	a.mu.Unlock()
+}
+
+// persistApprovals saves the approval queue to the monitored directory.
+func (a *App) persistApprovals() {
+	a.approvalsMu.Lock()
+	defer a.approvalsMu.Unlock()
+	a.mu.Lock()
+	dir := a.monitoredDir
+	browsing := a.archive != nil
+	raw, err := json.MarshalIndent(&approvalState{
+		Approved:       a.approved,
+		ApprovedHashes: a.approvedHashes,
+		Pending:        a.pending,
+		PendingSeq:     a.pendingSeq,
+		Rejected:       a.rejected,
+	}, "", "  ")
+	a.mu.Unlock()
+	if dir == "" || browsing {
+		return
+	}
+	if err == nil {
+		err = writeApprovals(dir, raw)
+	}
+	if err != nil {
+		resultsLog.Error("Failed to save approvals", "error", err)
+	}
+}
+
+// SetHoldForApproval turns the approval queue on or off. When enabled, the results
+// already in the monitored directory are treated as approved and every new save is
+// held in the pending queue. Disabling it publishes nothing that is still pending:
+// held saves are rejected, and stay unpublished until their file is saved again.
+func (a *App) SetHoldForApproval(enabled bool) {
+	var approved map[string]*LifData
+	hashes := make(map[string]string)
+	if enabled && a.monitoredDir != "" {
+		approved = make(map[string]*LifData)
+		results, err := a.scanResults()
+		if err != nil {
+			resultsLog.Error("Failed to scan results for approval mode", "error", err)
+		}
+		for _, data := range results {
+			approved[data.FileName] = data
+			hash, err := fileHash(filepath.Join(a.monitoredDir, data.FileName))
+			if err == nil && !a.heldBack(data.FileName, hash) {
+				hashes[data.FileName] = hash
+			} else {
+				// scanResults fell back to the approved version of a held back file.
+				hashes[data.FileName] = a.approvedHash(data.FileName)
+			}
+		}
+	}
+	a.mu.Lock()
+	a.holdForApproval = enabled
+	if enabled {
+		a.approved = approved
+		a.approvedHashes = hashes
+	} else {
+		if a.rejected == nil {
+			a.rejected = make(map[string]string)
+		}
+		for _, p := range a.pending {
+			a.rejected[p.Data.FileName] = p.Hash
+		}
+	}
+	a.pending = nil
+	a.mu.Unlock()
+	resultsLog.Info("Hold for approval updated", "enabled", enabled)
+	a.persistApprovals()
+	a.scheduleSave()
+}
+
+// heldBack reports whether the contents of a result file, identified by hash, are
+// still waiting for approval or were rejected, and so must not be published.
+func (a *App) heldBack(fileName string, hash string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if hash == "" {
+		return false
+	}
+	if a.rejected[fileName] == hash {
+		return true
+	}
+	for _, p := range a.pending {
+		if p.Data.FileName == fileName && p.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// approvedHash returns the hash of the approved contents of a result file, if any.
+func (a *App) approvedHash(fileName string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.approvedHashes[fileName]
+}
+
+// approvedResult returns a copy of the approved version of a result file with the
+// current overrides applied, or nil if it has never been approved.
+func (a *App) approvedResult(fileName string) *LifData {
+	a.mu.Lock()
+	approved := a.approved[fileName]
+	a.mu.Unlock()
+	if approved == nil {
+		return nil
+	}
+	data := *approved
+	a.applyOverrides(&data)
+	return &data
+}
+
+// reconcileApprovals brings the approval queue loaded from approvalsFile up to date
+// with the result files on disk when monitoring starts. Files saved while nothing was
+// watching are held like any other save. If the directory has no approval record yet,
+// the results already in it are treated as approved.
+func (a *App) reconcileApprovals(seeded map[string]*LifData, hashes map[string]string) {
+	a.mu.Lock()
+	if a.approved == nil {
+		a.approved = make(map[string]*LifData, len(seeded))
+		a.approvedHashes = make(map[string]string, len(seeded))
+		for name, data := range seeded {
+			a.approved[name] = data
+			a.approvedHashes[name] = hashes[name]
+		}
+		a.mu.Unlock()
+		a.persistApprovals()
+		return
+	}
+	var held []*LifData
+	for name, data := range seeded {
+		hash := hashes[name]
+		if a.approvedHashes[name] == hash || a.rejected[name] == hash {
+			continue
+		}
+		queued := false
+		for _, p := range a.pending {
+			if p.Data.FileName == name && p.Hash == hash {
+				queued = true
+				break
+			}
+		}
+		if !queued {
+			held = append(held, data)
+		}
+	}
+	a.mu.Unlock()
+	sort.Slice(held, func(i, j int) bool {
+		return held[i].ModifiedTime < held[j].ModifiedTime
+	})
+	for _, data := range held {
+		a.mu.Lock()
+		prev := a.approved[data.FileName]
+		a.mu.Unlock()
+		a.queuePending(data, diffLifData(prev, data), hashes[data.FileName])
+	}
+}
+
+// GetHoldForApproval reports whether new results are held for approval.
+func (a *App) GetHoldForApproval() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.holdForApproval
+}
+
+// queuePending adds a result to the pending queue, replacing any earlier pending
+// save of the same file. hash identifies the file contents that were held.
+func (a *App) queuePending(data *LifData, diff *ResultDiff, hash string) {
+	a.mu.Lock()
+	a.pendingSeq++
+	pending := &PendingResult{
+		ID:         strconv.Itoa(a.pendingSeq),
+		Data:       data,
+		ReceivedAt: time.Now().Unix(),
+		Diff:       diff,
+		Hash:       hash,
+	}
+	queue := make([]*PendingResult, 0, len(a.pending)+1)
+	for _, p := range a.pending {
+		if p.Data.FileName != data.FileName {
+			queue = append(queue, p)
+		}
+	}
+	a.pending = append(queue, pending)
+	a.mu.Unlock()
+	a.persistApprovals()
+
+	resultsLog.Info("Result held for approval", "file", data.FileName, "id", pending.ID)
+	a.emitEvent("pending-result", pending)
+}
+
+// GetPendingResults returns the results waiting for approval, oldest first.
+func (a *App) GetPendingResults() []*PendingResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	result := make([]*PendingResult, len(a.pending))
+	copy(result, a.pending)
+	return result
+}
+
+// pendingDetail describes a pending result for the audit journal.
+func pendingDetail(id string, data *LifData) string {
+	return fmt.Sprintf("pending %s, event %q, %d competitors", id, data.EventName, len(data.Competitors))
+}
+
+// takePending removes a pending result from the queue and returns it.
+func (a *App) takePending(id string) (*PendingResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, p := range a.pending {
+		if p.ID == id {
+			a.pending = append(a.pending[:i:i], a.pending[i+1:]...)
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no pending result with id %s", id)
+}
+
+// ApproveResult publishes a pending result to /latest-lif and /all-lif.
+func (a *App) ApproveResult(id string) error {
+	return a.approveResult(sourceDesktop, id)
+}
+
+// approveResult is ApproveResult, audited against source.
+func (a *App) approveResult(source string, id string) error {
+	p, err := a.takePending(id)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if a.approved == nil {
+		a.approved = make(map[string]*LifData)
+	}
+	a.approved[p.Data.FileName] = p.Data
+	if a.approvedHashes == nil {
+		a.approvedHashes = make(map[string]string)
+	}
+	a.approvedHashes[p.Data.FileName] = p.Hash
+	delete(a.rejected, p.Data.FileName)
+	a.latestData = p.Data
+	a.mu.Unlock()
+	a.persistApprovals()
+	a.interruptPlaylists(p.Data)
+	a.audit(AuditEntry{Action: auditApprove, Source: source, File: p.Data.FileName, Detail: pendingDetail(p.ID, p.Data)})
+	a.auditDisplays(source)
+	resultsLog.Info("Result approved", "file", p.Data.FileName, "id", id)
+	return nil
+}
+
+// RejectResult discards a pending result; the previously approved version, if any, stays public.
+func (a *App) RejectResult(id string) error {
+	return a.rejectResult(sourceDesktop, id)
+}
+
+// rejectResult is RejectResult, audited against source.
+func (a *App) rejectResult(source string, id string) error {
+	p, err := a.takePending(id)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if a.rejected == nil {
+		a.rejected = make(map[string]string)
+	}
+	a.rejected[p.Data.FileName] = p.Hash
+	a.mu.Unlock()
+	a.persistApprovals()
+	a.audit(AuditEntry{Action: auditReject, Source: source, File: p.Data.FileName, Detail: pendingDetail(p.ID, p.Data)})
+	resultsLog.Info("Result rejected", "file", p.Data.FileName, "id", id)
+	return nil
+}
+
+// UpdatePendingResult replaces the data of a pending result with an operator edited version.
+func (a *App) UpdatePendingResult(id string, data *LifData) error {
+	return a.updatePendingResult(sourceDesktop, id, data)
+}
+
+// updatePendingResult is UpdatePendingResult, audited against source.
+func (a *App) updatePendingResult(source string, id string, data *LifData) error {
+	if data == nil {
+		return fmt.Errorf("no result data supplied")
+	}
+	a.mu.Lock()
+	found := false
+	for _, p := range a.pending {
+		if p.ID == id {
+			// The file name identifies the result, so keep it whatever the edit says.
+			data.FileName = p.Data.FileName
+			p.Data = data
+			found = true
+			break
+		}
+	}
+	a.mu.Unlock()
+	if !found {
+		return fmt.Errorf("no pending result with id %s", id)
+	}
+	a.persistApprovals()
+	a.audit(AuditEntry{Action: auditEditPending, Source: source, File: data.FileName, Detail: pendingDetail(id, data)})
+	resultsLog.Info("Pending result edited", "file", data.FileName, "id", id)
+	return nil
+}
+
+// approvedResults returns the approved results sorted by ModifiedTime.
+func (a *App) approvedResults() []*LifData {
+	a.mu.Lock()
+	results := make([]*LifData, 0, len(a.approved))
+	for _, data := range a.approved {
+		results = append(results, data)
+	}
+	a.mu.Unlock()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ModifiedTime < results[j].ModifiedTime
+	})
+	return results
+}
+
+// registerApprovalRoutes adds the approval queue endpoints to the Fiber server.
+func registerApprovalRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/approval-mode", func(c *fiber.Ctx) error {
+		return c.JSON(map[string]interface{}{"enabled": app.GetHoldForApproval()})
+	})
+	fiberApp.Post("/approval-mode", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		app.SetHoldForApproval(req.Enabled)
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Get("/pending", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetPendingResults())
+	})
+	fiberApp.Put("/pending/:id", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var data LifData
+		if err := c.BodyParser(&data); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.updatePendingResult(app.requestSource(c), c.Params("id"), &data); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/pending/:id/approve", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.approveResult(app.requestSource(c), c.Params("id")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/pending/:id/reject", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.rejectResult(app.requestSource(c), c.Params("id")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLif writes a LIF result file with one competitor row per line of rows,
+// each given as "place,bib,lane,last,first,affiliation,time".
+func writeLif(t *testing.T, dir, name, event string, rows ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	content := "1,1,1," + event + ",\n" + strings.Join(rows, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTestApp returns an App monitoring dir without starting the watcher.
+func newTestApp(t *testing.T, dir string, hold bool) *App {
+	t.Helper()
+	app := NewApp()
+	app.settingsFile = filepath.Join(t.TempDir(), "settings.json")
+	app.monitoredDir = dir
+	app.holdForApproval = hold
+	app.initOverrides()
+	app.initApprovals()
+	app.seedResults()
+	return app
+}
+
+// saveResult simulates the watcher seeing a save of path.
+func saveResult(t *testing.T, app *App, path string) {
+	t.Helper()
+	data, err := app.parseResult(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := app.recordResult(data)
+	hash, err := fileHash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.queuePending(data, diff, hash)
+}
+
+// publishedTimes returns file name -> winning time of every published result.
+func publishedTimes(t *testing.T, app *App) map[string]string {
+	t.Helper()
+	results, err := app.GetAllLIFData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make(map[string]string)
+	for _, data := range results {
+		times[data.FileName] = data.Competitors[0].Time
+	}
+	return times
+}
+
+func pendingFiles(app *App) []string {
+	var files []string
+	for _, p := range app.GetPendingResults() {
+		files = append(files, p.Data.FileName)
+	}
+	return files
+}
+
+func TestApprovalsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.01")
+
+	app := newTestApp(t, dir, true)
+	if got := publishedTimes(t, app); got["a.lif"] != "12.01" {
+		t.Fatalf("results already saved should be approved, got %v", got)
+	}
+
+	saveResult(t, app, writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston AC,11.50"))
+	saveResult(t, app, writeLif(t, dir, "b.lif", "200m", "1,102,3,Jones,Bella,Sutton,25.10"))
+	edited := *app.GetPendingResults()[1].Data
+	edited.EventName = "200m Final"
+	if err := app.UpdatePendingResult(app.GetPendingResults()[1].ID, &edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RejectResult(app.GetPendingResults()[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart, or re-selecting the folder, keeps the queue as it was.
+	restarted := newTestApp(t, dir, true)
+	if got := pendingFiles(restarted); len(got) != 1 || got[0] != "b.lif" {
+		t.Fatalf("pending after restart = %v, want [b.lif]", got)
+	}
+	if got := restarted.GetPendingResults()[0].Data.EventName; got != "200m Final" {
+		t.Errorf("pending edit after restart = %q, want %q", got, "200m Final")
+	}
+	got := publishedTimes(t, restarted)
+	if got["a.lif"] != "12.01" || len(got) != 1 {
+		t.Errorf("published after restart = %v, want only the approved a.lif", got)
+	}
+
+	// Turning hold mode off publishes neither the rejected nor the pending save.
+	restarted.SetHoldForApproval(false)
+	got = publishedTimes(t, restarted)
+	if got["a.lif"] != "12.01" || len(got) != 1 {
+		t.Errorf("published with hold off = %v, want only the approved a.lif", got)
+	}
+
+	// Saving a file again publishes it as usual.
+	writeLif(t, dir, "b.lif", "200m", "1,102,3,Jones,Bella,Sutton,25.05")
+	if got := publishedTimes(t, restarted); got["b.lif"] != "25.05" {
+		t.Errorf("published after re-save = %v, want b.lif at 25.05", got)
+	}
+}
+
+func TestApprovalsHoldFilesSavedWhileStopped(t *testing.T) {
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.01")
+	newTestApp(t, dir, true)
+
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.00")
+	writeLif(t, dir, "c.lif", "400m", "1,103,5,Brown,Cara,Sutton,58.00")
+	restarted := newTestApp(t, dir, true)
+	pending := pendingFiles(restarted)
+	if len(pending) != 2 {
+		t.Fatalf("pending = %v, want a.lif and c.lif", pending)
+	}
+	got := publishedTimes(t, restarted)
+	if got["a.lif"] != "12.01" || len(got) != 1 {
+		t.Errorf("published = %v, want only the approved a.lif", got)
+	}
+}
@@ -0,0 +1,512 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// archiveFormat identifies a meet archive in its manifest.
+	archiveFormat = "polyfield-archive"
+	// archiveVersion is the archive layout written by this version. Archives with
+	// a higher version were written by a newer app and are refused.
+	archiveVersion = 1
+	// archiveExtension is the file extension of meet archives, which are zip files.
+	archiveExtension = ".pfarchive"
+)
+
+// Files in a meet archive, besides media/<id> for each media item.
+const (
+	archiveManifestFile = "manifest.json"
+	archiveResultsFile  = "results.json"
+	archiveOverrides    = "overrides.json"
+	archiveClubs        = "club-acronyms.json"
+	archiveDisplayFile  = "display.json" // Display channels and playlists
+	archiveJournalDir   = "journals/"    // Amendment and audit journals, copied as they are
+	archiveMediaDir     = "media/"
+)
+
+// ArchiveManifest describes a meet archive.
+type ArchiveManifest struct {
+	Format     string      `json:"format"`  // Always 'polyfield-archive'
+	Version    int         `json:"version"` // Archive layout version
+	AppVersion string      `json:"appVersion"`
+	Created    int64       `json:"created"` // Unix time
+	SourceDir  string      `json:"sourceDir"`
+	Meet       Meet        `json:"meet"`
+	Results    int         `json:"results"`
+	Media      []MediaItem `json:"media"`
+	Path       string      `json:"path,omitempty"` // Where the archive was loaded from, not stored
+}
+
+// archiveDisplay is the display setup saved in an archive.
+type archiveDisplay struct {
+	Channels  map[string]*DisplayState `json:"channels"`
+	Playlists []Playlist               `json:"playlists"`
+}
+
+// meetArchive is a meet archive loaded for browsing.
+type meetArchive struct {
+	manifest   ArchiveManifest
+	results    []*LifData
+	overrides  []ResultOverride
+	acronyms   map[string]string
+	amendments []*ResultDiff // newest first
+	media      map[string]*zip.File
+	zip        *zip.ReadCloser
+}
+
+// archiveUnsafeName matches the characters replaced in suggested archive file names.
+var archiveUnsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// archiveName is the suggested file name for an archive of the current meet.
+func (a *App) archiveName() string {
+	name := a.GetMeet().Name
+	if name == "" {
+		name = filepath.Base(a.GetMonitoredDirectory())
+	}
+	name = strings.Trim(archiveUnsafeName.ReplaceAllString(name, "-"), "-")
+	if name == "" || name == "." {
+		name = "meet"
+	}
+	return fmt.Sprintf("%s_%s%s", name, time.Now().Format("20060102"), archiveExtension)
+}
+
+// archiveMediaIDs returns the media library IDs used by display channels and playlists.
+func archiveMediaIDs(display archiveDisplay) []string {
+	used := make(map[string]bool)
+	for _, state := range display.Channels {
+		if state.ImageID != "" {
+			used[state.ImageID] = true
+		}
+		for _, id := range state.SlideshowIDs {
+			used[id] = true
+		}
+	}
+	for _, playlist := range display.Playlists {
+		for _, item := range playlist.Items {
+			if item.MediaID != "" {
+				used[item.MediaID] = true
+			}
+		}
+	}
+	ids := make([]string, 0, len(used))
+	for id := range used {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// writeJSONToZip adds a JSON file to an archive.
+func writeJSONToZip(archive *zip.Writer, name string, value interface{}) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeArchive writes the current meet to out as a meet archive: every published
+// result, the corrections, club acronyms, meet details, display setup, the media it
+// uses and the amendment and audit journals.
+func (a *App) writeArchive(out io.Writer) (*ArchiveManifest, error) {
+	if a.archiveOpen() {
+		return nil, fmt.Errorf("an archive is open, close it to archive the current meet")
+	}
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return nil, fmt.Errorf("no directory selected")
+	}
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, err
+	}
+	settings := a.currentSettings()
+	display := archiveDisplay{Channels: settings.Channels, Playlists: settings.Playlists}
+	if settings.DisplayState != nil {
+		display.Channels[defaultChannel] = settings.DisplayState
+	}
+	a.mu.Lock()
+	acronyms := a.customClubAcronyms
+	a.mu.Unlock()
+	if acronyms == nil {
+		acronyms = map[string]string{}
+	}
+
+	manifest := &ArchiveManifest{
+		Format:     archiveFormat,
+		Version:    archiveVersion,
+		AppVersion: appVersion,
+		Created:    time.Now().Unix(),
+		SourceDir:  dir,
+		Meet:       a.GetMeet(),
+		Results:    len(results),
+		Media:      []MediaItem{},
+	}
+	var mediaPaths []string
+	for _, id := range archiveMediaIDs(display) {
+		path, item, err := a.mediaPath(id)
+		if err != nil {
+			continue
+		}
+		manifest.Media = append(manifest.Media, *item)
+		mediaPaths = append(mediaPaths, path)
+	}
+
+	archive := zip.NewWriter(out)
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{archiveManifestFile, manifest},
+		{archiveResultsFile, results},
+		{archiveOverrides, a.GetOverrides()},
+		{archiveClubs, acronyms},
+		{archiveDisplayFile, display},
+	}
+	for _, file := range files {
+		if err := writeJSONToZip(archive, file.name, file.value); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", file.name, err)
+		}
+	}
+	for _, name := range []string{amendmentLogFile, auditLogFile} {
+		if err := addFileToZip(archive, filepath.Join(dir, name), archiveJournalDir+name); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to add %s: %v", name, err)
+		}
+	}
+	for _, path := range mediaPaths {
+		if err := addFileToZip(archive, path, archiveMediaDir+filepath.Base(path)); err != nil {
+			return nil, fmt.Errorf("failed to add media %s: %v", filepath.Base(path), err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// saveArchive writes an archive of the current meet to path.
+func (a *App) saveArchive(path string) (*ArchiveManifest, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %v", err)
+	}
+	manifest, err := a.writeArchive(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	resultsLog.Info("Meet archived", "path", path, "results", manifest.Results, "media", len(manifest.Media))
+	return manifest, nil
+}
+
+// readZipJSON decodes a JSON file in an archive. A missing file leaves value unchanged.
+func readZipJSON(files map[string]*zip.File, name string, value interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return nil
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := json.NewDecoder(reader).Decode(value); err != nil {
+		return fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	return nil
+}
+
+// readArchive opens a meet archive for browsing. The caller closes its zip.
+func readArchive(path string) (*meetArchive, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %v", err)
+	}
+	archive := &meetArchive{zip: reader, media: make(map[string]*zip.File), acronyms: map[string]string{}}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+	err = func() error {
+		if _, ok := files[archiveManifestFile]; !ok {
+			return fmt.Errorf("not a meet archive")
+		}
+		if err := readZipJSON(files, archiveManifestFile, &archive.manifest); err != nil {
+			return err
+		}
+		if archive.manifest.Format != archiveFormat {
+			return fmt.Errorf("not a meet archive")
+		}
+		if archive.manifest.Version > archiveVersion {
+			return fmt.Errorf("archive version %d was written by a newer version of the app (%s)",
+				archive.manifest.Version, archive.manifest.AppVersion)
+		}
+		if err := readZipJSON(files, archiveResultsFile, &archive.results); err != nil {
+			return err
+		}
+		if err := readZipJSON(files, archiveOverrides, &archive.overrides); err != nil {
+			return err
+		}
+		if err := readZipJSON(files, archiveClubs, &archive.acronyms); err != nil {
+			return err
+		}
+		if file, ok := files[archiveJournalDir+amendmentLogFile]; ok {
+			amendments, err := readAmendmentJournal(file)
+			if err != nil {
+				return err
+			}
+			archive.amendments = amendments
+		}
+		for _, item := range archive.manifest.Media {
+			if file, ok := files[archiveMediaDir+item.ID]; ok {
+				archive.media[item.ID] = file
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	if archive.results == nil {
+		archive.results = []*LifData{}
+	}
+	sort.Slice(archive.results, func(i, j int) bool {
+		return archive.results[i].ModifiedTime < archive.results[j].ModifiedTime
+	})
+	archive.manifest.Path = path
+	return archive, nil
+}
+
+// readAmendmentJournal reads the amendment journal in an archive, newest first.
+func readAmendmentJournal(file *zip.File) ([]*ResultDiff, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var amendments []*ResultDiff
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var diff ResultDiff
+		if err := json.Unmarshal(scanner.Bytes(), &diff); err == nil {
+			amendments = append([]*ResultDiff{&diff}, amendments...)
+		}
+	}
+	return amendments, scanner.Err()
+}
+
+// loadArchive opens a meet archive and serves it, read-only, instead of the
+// monitored directory until CloseArchive is called.
+func (a *App) loadArchive(path string) (*ArchiveManifest, error) {
+	archive, err := readArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	previous := a.archive
+	a.archive = archive
+	a.mu.Unlock()
+	if previous != nil {
+		previous.zip.Close()
+	}
+	resultsLog.Info("Archive opened", "path", path, "meet", archive.manifest.Meet.Name, "results", len(archive.results))
+	a.emitEvent("archive-changed")
+	go a.startMDNS()
+	manifest := archive.manifest
+	return &manifest, nil
+}
+
+// CloseArchive stops serving the open archive and goes back to the monitored directory.
+func (a *App) CloseArchive() {
+	a.mu.Lock()
+	archive := a.archive
+	a.archive = nil
+	a.mu.Unlock()
+	if archive == nil {
+		return
+	}
+	archive.zip.Close()
+	resultsLog.Info("Archive closed", "path", archive.manifest.Path)
+	a.emitEvent("archive-changed")
+	go a.startMDNS()
+}
+
+// GetArchiveInfo returns the manifest of the open archive, or nil if none is open.
+func (a *App) GetArchiveInfo() *ArchiveManifest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive == nil {
+		return nil
+	}
+	manifest := a.archive.manifest
+	return &manifest
+}
+
+// archiveOpen reports whether an archive is being served.
+func (a *App) archiveOpen() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.archive != nil
+}
+
+// archiveReadOnly returns an error if an archive is open, for changes that would
+// otherwise alter the results being browsed.
+func (a *App) archiveReadOnly() error {
+	if a.archiveOpen() {
+		return fmt.Errorf("an archive is open and is read-only")
+	}
+	return nil
+}
+
+// archivedResults returns the results of the open archive, or nil if none is open.
+func (a *App) archivedResults() []*LifData {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive == nil {
+		return nil
+	}
+	return a.archive.results
+}
+
+// archivedMedia returns an item from the open archive's media, if it has it.
+func (a *App) archivedMedia(id string) (*zip.File, *MediaItem, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive == nil {
+		return nil, nil, false
+	}
+	file, ok := a.archive.media[id]
+	if !ok {
+		return nil, nil, false
+	}
+	for i := range a.archive.manifest.Media {
+		if item := &a.archive.manifest.Media[i]; item.ID == id {
+			return file, item, true
+		}
+	}
+	return nil, nil, false
+}
+
+// ArchiveMeet asks where to save an archive of the current meet and writes it. It
+// returns the path written, or an empty string if cancelled.
+func (a *App) ArchiveMeet() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Archive Meet",
+		DefaultFilename: a.archiveName(),
+		Filters:         []runtime.FileFilter{{DisplayName: "Meet archives", Pattern: "*" + archiveExtension}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if _, err := a.saveArchive(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// OpenArchive asks for a meet archive and serves it instead of the monitored
+// directory. It returns nil if cancelled.
+func (a *App) OpenArchive() (*ArchiveManifest, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Open Meet Archive",
+		Filters: []runtime.FileFilter{{DisplayName: "Meet archives", Pattern: "*" + archiveExtension}},
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return a.loadArchive(path)
+}
+
+// runArchive archives a results directory from the command line, without starting
+// the server. Every result file is included, whether or not it was approved.
+func runArchive(args []string) error {
+	flags := flag.NewFlagSet("archive", flag.ContinueOnError)
+	output := flags.String("o", "", "archive file to write (default <meet name>_<date>"+archiveExtension+")")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s archive [-o file] results-directory\n", filepath.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("give one results directory")
+	}
+	if strings.HasSuffix(strings.ToLower(flags.Arg(0)), archiveExtension) {
+		return fmt.Errorf("%s is already a meet archive", flags.Arg(0))
+	}
+	app, err := newCommandApp(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	path := *output
+	if path == "" {
+		path = app.archiveName()
+	}
+	manifest, err := app.saveArchive(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Archived %d results and %d media files to %s\n", manifest.Results, len(manifest.Media), path)
+	return nil
+}
+
+// registerArchiveRoutes adds the meet archive endpoints to the Fiber server.
+func registerArchiveRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/archive", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		if app.archiveOpen() {
+			return c.Status(409).JSON(map[string]interface{}{"error": "an archive is open, close it to archive the current meet"})
+		}
+		c.Set("Content-Type", "application/zip")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, app.archiveName()))
+		_, err := app.writeArchive(c.Response().BodyWriter())
+		return err
+	})
+	fiberApp.Get("/archive/info", func(c *fiber.Ctx) error {
+		if info := app.GetArchiveInfo(); info != nil {
+			info.Path = filepath.Base(info.Path)
+			return c.JSON(info)
+		}
+		return c.JSON(map[string]interface{}{})
+	})
+	fiberApp.Post("/archive/open", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Path string `json:"path"` // On the server
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		manifest, err := app.loadArchive(req.Path)
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(manifest)
+	})
+	fiberApp.Post("/archive/close", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		app.CloseArchive()
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
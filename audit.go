@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// auditLogFile is the append-only journal of what was shown on screens, kept in the
+// monitored directory next to the amendment log.
+const auditLogFile = "polyfield-audit.jsonl"
+
+// Audit actions.
+const (
+	auditResultFile      = "result-file"      // A result file was seen, with its hash
+	auditDisplay         = "display"          // What a display channel shows changed
+	auditApprove         = "approve"          // A held result was approved
+	auditReject          = "reject"           // A held result was rejected
+	auditEditPending     = "edit-pending"     // A held result was edited before approval
+	auditOverrideSet     = "override-set"     // A result correction was added or changed
+	auditOverrideRemoved = "override-removed" // A result correction was removed
+)
+
+// Sources of audited changes made by the app itself rather than a person.
+const (
+	sourceDesktop     = "desktop"
+	sourceWatcher     = "watcher"
+	sourcePlaylist    = "playlist"
+	sourceReplication = "replication"
+	sourceApp         = "app" // State restored when a meet is opened
+)
+
+// AuditEntry is one line of the audit journal.
+type AuditEntry struct {
+	Time    int64  `json:"time"`   // Unix time in milliseconds
+	Action  string `json:"action"` // See the audit action constants
+	Source  string `json:"source"` // 'desktop', 'watcher', 'playlist', or the address and key of a web control panel
+	Channel string `json:"channel,omitempty"`
+	File    string `json:"file,omitempty"`
+	Hash    string `json:"hash,omitempty"` // SHA-256 of the result file's contents
+	Detail  string `json:"detail"`
+}
+
+// AuditQuery filters the audit journal. Empty fields match everything.
+type AuditQuery struct {
+	Action  string `json:"action"`
+	Source  string `json:"source"` // Matches any source containing this text
+	Channel string `json:"channel"`
+	File    string `json:"file"`
+	Since   int64  `json:"since"` // Unix time in milliseconds
+	Until   int64  `json:"until"` // Unix time in milliseconds
+	Limit   int    `json:"limit"` // Newest entries returned, 0 for all
+}
+
+// displaySummary is what a display channel shows, as recorded in the journal.
+type displaySummary struct {
+	Mode         string
+	View         string
+	Text         string
+	ImageID      string
+	Slideshow    string
+	ResultFile   string
+	ResultEvent  string
+	ResultTime   int64
+	LayoutTheme  string
+	RotationMode string
+	ShowBib      bool
+	Playlist     string
+}
+
+// summariseDisplay reduces a display state to what is visible on screen.
+func summariseDisplay(state *DisplayState) displaySummary {
+	summary := displaySummary{
+		Mode:         state.Mode,
+		View:         state.View,
+		Text:         state.ActiveText,
+		ImageID:      state.ImageID,
+		Slideshow:    strings.Join(state.SlideshowIDs, " "),
+		LayoutTheme:  state.LayoutTheme,
+		RotationMode: state.RotationMode,
+		ShowBib:      state.ShowBib,
+		Playlist:     state.Playlist,
+	}
+	if state.CurrentLIF != nil {
+		summary.ResultFile = state.CurrentLIF.FileName
+		summary.ResultEvent = state.CurrentLIF.EventName
+		summary.ResultTime = state.CurrentLIF.ModifiedTime
+	}
+	return summary
+}
+
+// describe formats a display summary for the journal.
+func (s displaySummary) describe() string {
+	parts := []string{"mode=" + s.Mode}
+	if s.View != "" {
+		parts = append(parts, "view="+s.View)
+	}
+	switch s.Mode {
+	case "text":
+		parts = append(parts, fmt.Sprintf("text=%q", s.Text))
+	case "screensaver":
+		if s.Slideshow != "" {
+			parts = append(parts, "slideshow="+s.Slideshow)
+		} else if s.ImageID != "" {
+			parts = append(parts, "image="+s.ImageID)
+		}
+	}
+	if s.ResultFile != "" {
+		parts = append(parts, fmt.Sprintf("result=%s event=%q", s.ResultFile, s.ResultEvent))
+	}
+	parts = append(parts, "theme="+s.LayoutTheme, "rotation="+s.RotationMode, "showBib="+strconv.FormatBool(s.ShowBib))
+	if s.Playlist != "" {
+		parts = append(parts, fmt.Sprintf("playlist=%q", s.Playlist))
+	}
+	return strings.Join(parts, " ")
+}
+
+// appendAuditLocked appends entries to the journal in dir. The caller must hold a.auditMu.
+func appendAuditLocked(dir string, entries ...AuditEntry) {
+	if dir == "" || len(entries) == 0 {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(dir, auditLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		resultsLog.Error("Failed to open audit journal", "error", err)
+		return
+	}
+	defer f.Close()
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			resultsLog.Error("Failed to encode audit entry", "error", err)
+			continue
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			resultsLog.Error("Failed to write audit journal", "error", err)
+			return
+		}
+	}
+}
+
+// audit records an action in the journal of the monitored directory.
+func (a *App) audit(entry AuditEntry) {
+	dir := a.GetMonitoredDirectory()
+	entry.Time = time.Now().UnixMilli()
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	appendAuditLocked(dir, entry)
+}
+
+// auditDisplays records every display channel whose visible state has changed
+// since it was last recorded. It is called after anything that changes a channel,
+// so a change is recorded once, against the source that made it.
+func (a *App) auditDisplays(source string) {
+	a.mu.Lock()
+	dir := a.monitoredDir
+	summaries := make(map[string]displaySummary, len(a.channels))
+	for name, state := range a.channels {
+		summaries[name] = summariseDisplay(state)
+	}
+	a.mu.Unlock()
+	if dir == "" {
+		return
+	}
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now().UnixMilli()
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	var entries []AuditEntry
+	for _, name := range names {
+		summary := summaries[name]
+		if last, ok := a.auditDisplayed[name]; ok && last == summary {
+			continue
+		}
+		a.auditDisplayed[name] = summary
+		entries = append(entries, AuditEntry{
+			Time:    now,
+			Action:  auditDisplay,
+			Source:  source,
+			Channel: name,
+			File:    summary.ResultFile,
+			Detail:  summary.describe(),
+		})
+	}
+	appendAuditLocked(dir, entries...)
+}
+
+// auditResultFile records a result file the first time each version of it is seen.
+// data is nil if the file couldn't be parsed.
+func (a *App) auditResultFile(path string, data *LifData, parseErr error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	hash := hashContents(raw)
+	name := filepath.Base(path)
+	detail := fmt.Sprintf("%d bytes", len(raw))
+	if data != nil {
+		detail += fmt.Sprintf(", event %q, %d competitors", data.EventName, len(data.Competitors))
+	} else if parseErr != nil {
+		detail += ", failed to parse: " + parseErr.Error()
+	}
+	dir := a.GetMonitoredDirectory()
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	if a.auditHashes[name] == hash {
+		return
+	}
+	a.auditHashes[name] = hash
+	appendAuditLocked(dir, AuditEntry{
+		Time:   time.Now().UnixMilli(),
+		Action: auditResultFile,
+		Source: sourceWatcher,
+		File:   name,
+		Hash:   hash,
+		Detail: detail,
+	})
+}
+
+// readAuditJournal reads every entry of the journal in dir, oldest first.
+func readAuditJournal(dir string) ([]AuditEntry, error) {
+	f, err := os.Open(filepath.Join(dir, auditLogFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Keep going past a line damaged by a crash mid-write.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// loadAudit restores the hashes of the result files already recorded in dir's
+// journal, so that reopening a meet doesn't record them again. Display channels
+// are recorded afresh, documenting what the screens show when the meet is opened.
+// Opening the directory already loaded changes nothing.
+func (a *App) loadAudit(dir string) {
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	if dir == a.auditDir {
+		return
+	}
+	entries, err := readAuditJournal(dir)
+	if err != nil {
+		resultsLog.Error("Failed to read audit journal", "error", err)
+	}
+	a.auditDir = dir
+	a.auditHashes = make(map[string]string)
+	a.auditDisplayed = make(map[string]displaySummary)
+	for _, entry := range entries {
+		if entry.Action == auditResultFile {
+			a.auditHashes[entry.File] = entry.Hash
+		}
+	}
+}
+
+// GetAuditLog returns the audit journal entries matching the query, newest first.
+func (a *App) GetAuditLog(query AuditQuery) ([]AuditEntry, error) {
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return nil, fmt.Errorf("no directory selected")
+	}
+	a.auditMu.Lock()
+	entries, err := readAuditJournal(dir)
+	a.auditMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	result := []AuditEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if (query.Action != "" && entry.Action != query.Action) ||
+			(query.Source != "" && !strings.Contains(entry.Source, query.Source)) ||
+			(query.Channel != "" && entry.Channel != query.Channel) ||
+			(query.File != "" && entry.File != query.File) ||
+			(query.Since != 0 && entry.Time < query.Since) ||
+			(query.Until != 0 && entry.Time > query.Until) {
+			continue
+		}
+		result = append(result, entry)
+		if query.Limit > 0 && len(result) == query.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// writeAuditCSV writes audit entries as CSV, with times in local time.
+func writeAuditCSV(out io.Writer, entries []AuditEntry) error {
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"time", "action", "source", "channel", "file", "hash", "detail"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			time.UnixMilli(entry.Time).Format("2006-01-02 15:04:05.000"),
+			entry.Action, entry.Source, entry.Channel, entry.File, entry.Hash, entry.Detail,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportAuditLog asks where to save the whole audit journal and writes it as 'csv'
+// or 'json', oldest entry first. It returns the path written, or an empty string
+// if cancelled.
+func (a *App) ExportAuditLog(format string) (string, error) {
+	if format != "csv" && format != "json" {
+		return "", fmt.Errorf("unknown format %q, use csv or json", format)
+	}
+	entries, err := a.GetAuditLog(AuditQuery{})
+	if err != nil {
+		return "", err
+	}
+	// Oldest first reads naturally in a spreadsheet.
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Audit Trail",
+		DefaultFilename: "polyfield-audit." + format,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to export audit trail: %v", err)
+	}
+	defer f.Close()
+	if format == "csv" {
+		err = writeAuditCSV(f, entries)
+	} else {
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(entries)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to export audit trail: %v", err)
+	}
+	resultsLog.Info("Audit trail exported", "path", path)
+	return path, nil
+}
+
+// registerAuditRoutes adds the audit journal endpoint to the Fiber server.
+// ?format=csv downloads the matching entries as CSV, oldest first.
+func registerAuditRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/audit", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		query := AuditQuery{
+			Action:  c.Query("action"),
+			Source:  c.Query("source"),
+			Channel: c.Query("channel"),
+			File:    c.Query("file"),
+			Since:   int64(c.QueryInt("since")),
+			Until:   int64(c.QueryInt("until")),
+			Limit:   c.QueryInt("limit"),
+		}
+		entries, err := app.GetAuditLog(query)
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if c.Query("format") != "csv" {
+			return c.JSON(entries)
+		}
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", `attachment; filename="polyfield-audit.csv"`)
+		return writeAuditCSV(c.Response().BodyWriter(), entries)
+	})
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Roles, in increasing order of access. Each role can do everything the roles below it can.
+const (
+	roleViewer   = "viewer"   // Read control data such as the pending queue and display registry
+	roleOperator = "operator" // Change what the displays show and approve results
+	roleAdmin    = "admin"    // Manage access keys
+)
+
+var roleRank = map[string]int{roleViewer: 1, roleOperator: 2, roleAdmin: 3}
+
+// Fiber locals set by requireRole, so a handler can tell who made the request
+// without checking the key again.
+const (
+	localsRole  = "authRole"
+	localsKeyID = "authKeyID"
+)
+
+const (
+	// sessionCookie is the cookie web control panels are given when they log in.
+	sessionCookie = "polyfield_session"
+	// sessionLifetime is how long a web control panel stays logged in.
+	sessionLifetime = 12 * time.Hour
+	// maxLoginFailures is how many bad keys an address can try before it is locked out.
+	maxLoginFailures = 5
+	// loginLockout is how long an address is locked out after too many bad keys.
+	loginLockout = time.Minute
+	// loginAttemptWindow is how long a bad key counts against an address.
+	loginAttemptWindow = 15 * time.Minute
+	// maxTrackedAddresses caps the number of addresses with failed logins that are
+	// remembered, so a flood of addresses can't grow the table without limit.
+	maxTrackedAddresses = 1024
+	// minPINLength is the shortest PIN accepted for an access key.
+	minPINLength = 8
+	// keyHashIterations is the PBKDF2 work factor for stored access key hashes.
+	keyHashIterations = 10000
+)
+
+// AccessKey is a PIN or token that grants a role on the control endpoints. Only a
+// hash of the secret is stored; the secret itself is shown once when the key is created.
+type AccessKey struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Created  int64  `json:"created"`  // Unix time
+	LastUsed int64  `json:"lastUsed"` // Unix time, 0 if never used
+	Prefixed bool   `json:"prefixed"` // The secret starts with the ID, as generated tokens do; PINs and older keys don't
+	Hash     string `json:"hash,omitempty"`
+}
+
+// authSession is a logged in web control panel.
+type authSession struct {
+	keyID   string
+	role    string
+	expires time.Time
+}
+
+// loginAttempts counts failed logins from one address.
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// lockoutError is returned for credentials sent from an address that is locked out.
+type lockoutError struct {
+	wait time.Duration
+}
+
+func (e lockoutError) Error() string {
+	return fmt.Sprintf("too many failed logins, try again in %d seconds", int(e.wait.Seconds())+1)
+}
+
+// randomToken returns a random hex token, used for the operator token and display IDs.
+func randomToken() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// Fall back to a time based token rather than leaving control endpoints open.
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// deriveKeyHash returns the PBKDF2-SHA256 hash of a secret with the given salt.
+func deriveKeyHash(secret string, salt []byte) []byte {
+	hash, err := pbkdf2.Key(sha256.New, secret, salt, keyHashIterations, sha256.Size)
+	if err != nil {
+		// Only possible for invalid parameters, which are constants here.
+		panic(err)
+	}
+	return hash
+}
+
+// hashSecret returns the stored form of an access key secret: a random salt and
+// the PBKDF2 hash of the secret, hex encoded as 'salt$hash'.
+func hashSecret(secret string) string {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(salt) + "$" + hex.EncodeToString(deriveKeyHash(secret, salt))
+}
+
+// secretMatches reports whether a secret matches a stored hash. Hashes saved by
+// earlier versions are a plain SHA-256 of the secret, without a salt.
+func secretMatches(secret string, stored string) bool {
+	saltHex, hashHex, salted := strings.Cut(stored, "$")
+	if !salted {
+		sum := sha256.Sum256([]byte(secret))
+		return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(stored)) == 1
+	}
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return false
+	}
+	want, err := hex.DecodeString(hashHex)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(deriveKeyHash(secret, salt), want) == 1
+}
+
+// desktopOrigins are the origins of the desktop app's web view, which calls the
+// web server from another origin. Pages served by the web server itself are on its
+// own origin and need no CORS headers.
+var desktopOrigins = map[string]bool{
+	"wails://wails":           true,
+	"http://wails.localhost":  true,
+	"https://wails.localhost": true,
+}
+
+// allowedOrigin reports whether a cross-origin request from origin may read
+// responses: only the desktop app, and 'wails dev' on this machine, can.
+func allowedOrigin(origin string) bool {
+	if desktopOrigins[origin] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// accessKeysPath returns the location of the access key file. Keys are kept in the
+// user config directory rather than the results folder, which is often shared.
+func accessKeysPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "PolyField-Track", "access-keys.json"), nil
+}
+
+// loadAccessKeys reads the saved access keys. A missing file means no keys.
+func (a *App) loadAccessKeys() {
+	path, err := accessKeysPath()
+	if err != nil {
+		authLog.Error("Failed to locate access keys", "error", err)
+		return
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			authLog.Error("Failed to load access keys", "error", err)
+		}
+		return
+	}
+	var keys []*AccessKey
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		authLog.Error("Failed to read access keys", "error", err)
+		return
+	}
+	a.mu.Lock()
+	a.accessKeys = keys
+	a.mu.Unlock()
+	authLog.Info("Loaded access keys", "count", len(keys))
+}
+
+// saveAccessKeysLocked writes the access keys. The caller must hold a.mu.
+func (a *App) saveAccessKeysLocked() error {
+	path, err := accessKeysPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+	raw, err := json.MarshalIndent(a.accessKeys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode access keys: %v", err)
+	}
+	// The file only holds hashes, but there is no reason for other users to read it.
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		return fmt.Errorf("failed to save access keys: %v", err)
+	}
+	return nil
+}
+
+// GetOperatorToken returns the desktop app's own admin token. It is regenerated
+// every time the app starts and is never written to disk.
+func (a *App) GetOperatorToken() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.operatorToken
+}
+
+// GetAccessKeys returns the access keys, oldest first, without their hashes.
+func (a *App) GetAccessKeys() []AccessKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	result := make([]AccessKey, 0, len(a.accessKeys))
+	for _, key := range a.accessKeys {
+		entry := *key
+		entry.Hash = ""
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Created < result[j].Created })
+	return result
+}
+
+// CreateAccessKey adds an access key for a role and returns its secret, which is
+// not shown again. If pin is empty a random token is generated.
+func (a *App) CreateAccessKey(name string, role string, pin string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if _, ok := roleRank[role]; !ok {
+		return "", fmt.Errorf("unknown role %q, expected viewer, operator or admin", role)
+	}
+	id := randomToken()
+	secret := strings.TrimSpace(pin)
+	prefixed := secret == ""
+	if prefixed {
+		// The ID prefix lets the key be found without checking every hash.
+		secret = id + "." + randomToken() + randomToken()
+	} else if len(secret) < minPINLength {
+		return "", fmt.Errorf("PIN must be at least %d characters", minPINLength)
+	} else {
+		// PINs are looked up by checking every key without a prefix, so two can't
+		// share one. The hashes are checked without holding a.mu.
+		for _, key := range a.unprefixedKeys() {
+			if secretMatches(secret, key.Hash) {
+				return "", fmt.Errorf("that PIN is already in use, choose another")
+			}
+		}
+	}
+	key := &AccessKey{
+		ID:       id,
+		Name:     name,
+		Role:     role,
+		Created:  time.Now().Unix(),
+		Prefixed: prefixed,
+		Hash:     hashSecret(secret),
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.accessKeys = append(a.accessKeys, key)
+	if err := a.saveAccessKeysLocked(); err != nil {
+		a.accessKeys = a.accessKeys[:len(a.accessKeys)-1]
+		return "", err
+	}
+	authLog.Info("Access key created", "id", key.ID, "name", name, "role", role)
+	return secret, nil
+}
+
+// DeleteAccessKey revokes an access key and logs out any sessions using it.
+func (a *App) DeleteAccessKey(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys := make([]*AccessKey, 0, len(a.accessKeys))
+	found := false
+	for _, key := range a.accessKeys {
+		if key.ID == id {
+			found = true
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if !found {
+		return fmt.Errorf("no access key with id %s", id)
+	}
+	a.accessKeys = keys
+	for token, session := range a.sessions {
+		if session.keyID == id {
+			delete(a.sessions, token)
+		}
+	}
+	authLog.Info("Access key deleted", "id", id)
+	return a.saveAccessKeysLocked()
+}
+
+// unprefixedKeys returns a copy of the keys whose secret doesn't start with
+// their ID: PINs, and keys generated by earlier versions.
+func (a *App) unprefixedKeys() []AccessKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var keys []AccessKey
+	for _, key := range a.accessKeys {
+		if !key.Prefixed {
+			keys = append(keys, *key)
+		}
+	}
+	return keys
+}
+
+// authenticate returns the role granted by a secret and the ID of its access key,
+// or an empty role if it grants none. A generated key is found by the ID it starts
+// with, so only its hash is checked; other secrets are checked against every key
+// without a prefix. Hashes are checked without holding a.mu, which would otherwise
+// hold up the watcher and every other request while they are worked out.
+func (a *App) authenticate(secret string) (string, string) {
+	if secret == "" {
+		return "", ""
+	}
+	a.mu.Lock()
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(a.operatorToken)) == 1 {
+		a.mu.Unlock()
+		return roleAdmin, ""
+	}
+	var candidates []AccessKey
+	if id, _, ok := strings.Cut(secret, "."); ok {
+		for _, key := range a.accessKeys {
+			if key.Prefixed && key.ID == id {
+				candidates = append(candidates, *key)
+			}
+		}
+	}
+	a.mu.Unlock()
+	if len(candidates) == 0 {
+		candidates = a.unprefixedKeys()
+	}
+
+	for _, candidate := range candidates {
+		if !secretMatches(secret, candidate.Hash) {
+			continue
+		}
+		rehash := ""
+		if !strings.Contains(candidate.Hash, "$") {
+			// Replace an unsalted hash from an earlier version.
+			rehash = hashSecret(secret)
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		for _, key := range a.accessKeys {
+			if key.ID != candidate.ID {
+				continue
+			}
+			key.LastUsed = time.Now().Unix()
+			if rehash != "" && key.Hash == candidate.Hash {
+				key.Hash = rehash
+				if err := a.saveAccessKeysLocked(); err != nil {
+					authLog.Error("Failed to save access keys", "error", err)
+				}
+			}
+			return key.Role, key.ID
+		}
+		// Deleted while the hash was being checked.
+		return "", ""
+	}
+	return "", ""
+}
+
+// checkSecret authenticates a secret sent from address. Every credential check
+// goes through here, whether it is a login or a key sent with a request, so bad
+// keys count towards the same lockout. The caller must not hold a.mu.
+func (a *App) checkSecret(secret string, address string, now time.Time) (string, string, error) {
+	a.mu.Lock()
+	attempts, ok := a.loginAttempts[address]
+	if ok && now.Before(attempts.lockedUntil) {
+		a.mu.Unlock()
+		return "", "", lockoutError{wait: attempts.lockedUntil.Sub(now)}
+	}
+	// Count the attempt as a failure until the key has been checked, so guesses
+	// sent at the same time can't get past the lockout while hashes are checked.
+	if !ok || now.Sub(attempts.lastFailure) > loginAttemptWindow {
+		a.pruneLoginAttemptsLocked(now)
+		attempts = &loginAttempts{}
+		a.loginAttempts[address] = attempts
+	}
+	attempts.failures++
+	attempts.lastFailure = now
+	if attempts.failures >= maxLoginFailures {
+		attempts.failures = 0
+		attempts.lockedUntil = now.Add(loginLockout)
+		authLog.Warn("Login locked out", "address", address, "failures", maxLoginFailures)
+	}
+	a.mu.Unlock()
+
+	role, keyID := a.authenticate(secret)
+	if role == "" {
+		return "", "", fmt.Errorf("invalid key")
+	}
+	a.mu.Lock()
+	delete(a.loginAttempts, address)
+	a.mu.Unlock()
+	return role, keyID, nil
+}
+
+// pruneLoginAttemptsLocked forgets addresses whose failures have expired and, if
+// the table is still full, the address that failed longest ago. The caller must
+// hold a.mu.
+func (a *App) pruneLoginAttemptsLocked(now time.Time) {
+	for address, attempts := range a.loginAttempts {
+		if now.Sub(attempts.lastFailure) > loginAttemptWindow && !now.Before(attempts.lockedUntil) {
+			delete(a.loginAttempts, address)
+		}
+	}
+	for len(a.loginAttempts) >= maxTrackedAddresses {
+		oldest := ""
+		for address, attempts := range a.loginAttempts {
+			if oldest == "" || attempts.lastFailure.Before(a.loginAttempts[oldest].lastFailure) {
+				oldest = address
+			}
+		}
+		delete(a.loginAttempts, oldest)
+	}
+}
+
+// requestRole returns the role of a request. Credentials are accepted as an
+// Authorization: Bearer header, an X-Operator-Token header or a session cookie.
+// Keys aren't accepted in the URL, where they would end up in logs and browser
+// history.
+func (a *App) requestRole(c *fiber.Ctx) string {
+	role, _, _ := a.requestCredentials(c)
+	return role
+}
+
+// requestCredentials returns the role of a request and the ID of the access key it
+// used, which is empty for the operator token. A key sent from an address that is
+// locked out returns a lockoutError.
+func (a *App) requestCredentials(c *fiber.Ctx) (string, string, error) {
+	secret := strings.TrimSpace(strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+	if secret == "" {
+		secret = strings.TrimSpace(c.Get("X-Operator-Token"))
+	}
+	if secret != "" {
+		role, keyID, err := a.checkSecret(secret, c.IP(), time.Now())
+		if err == nil {
+			return role, keyID, nil
+		}
+		var locked lockoutError
+		if errors.As(err, &locked) {
+			return "", "", err
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if cookie := c.Cookies(sessionCookie); cookie != "" {
+		session, ok := a.sessions[cookie]
+		if ok && time.Now().Before(session.expires) {
+			return session.role, session.keyID, nil
+		}
+		delete(a.sessions, cookie)
+	}
+	return "", "", nil
+}
+
+// requestSource describes who made a request for the audit journal: the client
+// address and the name of its access key, or 'operator token' for the desktop
+// app's token (the admin key in headless mode). Behind requireRole it uses the
+// credentials requireRole already checked.
+func (a *App) requestSource(c *fiber.Ctx) string {
+	role, _ := c.Locals(localsRole).(string)
+	keyID, _ := c.Locals(localsKeyID).(string)
+	if role == "" {
+		role, keyID, _ = a.requestCredentials(c)
+	}
+	if role == "" {
+		return c.IP()
+	}
+	name := "operator token"
+	if keyID != "" {
+		a.mu.Lock()
+		name = "key " + keyID
+		for _, key := range a.accessKeys {
+			if key.ID == keyID {
+				name = fmt.Sprintf("key %q", key.Name)
+			}
+		}
+		a.mu.Unlock()
+	}
+	return c.IP() + " (" + name + ")"
+}
+
+// requireRole returns Fiber middleware that rejects requests without at least the given role.
+func (a *App) requireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		got, keyID, err := a.requestCredentials(c)
+		if err != nil {
+			return c.Status(429).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if got == "" {
+			return c.Status(401).JSON(map[string]interface{}{"error": "login required"})
+		}
+		if roleRank[got] < roleRank[role] {
+			return c.Status(403).JSON(map[string]interface{}{"error": role + " access required"})
+		}
+		c.Locals(localsRole, got)
+		c.Locals(localsKeyID, keyID)
+		return c.Next()
+	}
+}
+
+// login checks a key from a web control panel and starts a session for it.
+func (a *App) login(secret string, address string) (string, string, error) {
+	now := time.Now()
+	role, keyID, err := a.checkSecret(strings.TrimSpace(secret), address, now)
+	if err != nil {
+		return "", "", err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for token, session := range a.sessions {
+		if now.After(session.expires) {
+			delete(a.sessions, token)
+		}
+	}
+	token := randomToken() + randomToken()
+	a.sessions[token] = &authSession{keyID: keyID, role: role, expires: now.Add(sessionLifetime)}
+	if keyID != "" {
+		// Record when the key was last used.
+		if err := a.saveAccessKeysLocked(); err != nil {
+			authLog.Error("Failed to save access keys", "error", err)
+		}
+	}
+	authLog.Info("Login", "address", address, "role", role)
+	return token, role, nil
+}
+
+// registerAuthRoutes adds the login and access key endpoints to the Fiber server.
+func registerAuthRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Post("/auth/login", func(c *fiber.Ctx) error {
+		var req struct {
+			Key string `json:"key"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		token, role, err := app.login(req.Key, c.IP())
+		var locked lockoutError
+		if errors.As(err, &locked) {
+			return c.Status(429).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(401).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		c.Cookie(&fiber.Cookie{
+			Name:     sessionCookie,
+			Value:    token,
+			Path:     "/",
+			Expires:  time.Now().Add(sessionLifetime),
+			HTTPOnly: true,
+			Secure:   c.Context().IsTLS(),
+			SameSite: "Strict",
+		})
+		return c.JSON(map[string]interface{}{"success": true, "role": role})
+	})
+	fiberApp.Post("/auth/logout", func(c *fiber.Ctx) error {
+		if cookie := c.Cookies(sessionCookie); cookie != "" {
+			app.mu.Lock()
+			delete(app.sessions, cookie)
+			app.mu.Unlock()
+		}
+		c.ClearCookie(sessionCookie)
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Get("/auth/session", func(c *fiber.Ctx) error {
+		role := app.requestRole(c)
+		return c.JSON(map[string]interface{}{"loggedIn": role != "", "role": role})
+	})
+	fiberApp.Get("/auth/keys", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetAccessKeys())
+	})
+	fiberApp.Post("/auth/keys", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Name string `json:"name"`
+			Role string `json:"role"`
+			Pin  string `json:"pin"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		secret, err := app.CreateAccessKey(req.Name, req.Role, req.Pin)
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true, "key": secret})
+	})
+	fiberApp.Delete("/auth/keys/:id", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		if err := app.DeleteAccessKey(c.Params("id")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSecretMatches(t *testing.T) {
+	salted := hashSecret("correct horse")
+	tests := []struct {
+		name   string
+		secret string
+		stored string
+		want   bool
+	}{
+		{"salted match", "correct horse", salted, true},
+		{"salted mismatch", "correct horsf", salted, false},
+		// SHA-256 of "12345678", as saved by earlier versions.
+		{"unsalted match", "12345678", "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", true},
+		{"unsalted mismatch", "12345679", "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", false},
+		{"corrupt salt", "correct horse", "zz$" + strings.SplitN(salted, "$", 2)[1], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := secretMatches(tt.secret, tt.stored); got != tt.want {
+				t.Errorf("secretMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if hashSecret("correct horse") == salted {
+		t.Error("hashSecret() gave the same hash twice; it should be salted")
+	}
+}
+
+func TestCreateAccessKeyPIN(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	app := NewApp()
+	tests := []struct {
+		pin     string
+		wantErr bool
+	}{
+		{"1234", true},
+		{"1234567", true},
+		{"12345678", false},
+		{"12345678", true}, // already in use
+		{"", false},
+	}
+	for _, tt := range tests {
+		secret, err := app.CreateAccessKey("Track", roleOperator, tt.pin)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CreateAccessKey(pin %q) error = %v, wantErr %v", tt.pin, err, tt.wantErr)
+		}
+		if tt.pin == "" && len(secret) < 16 {
+			t.Errorf("generated token %q is too short", secret)
+		}
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"wails://wails", true},
+		{"http://wails.localhost", true},
+		{"http://localhost:34115", true},
+		{"http://192.168.1.20:3000", false},
+		{"http://evil.example", false},
+		{"https://localhost", false},
+	}
+	for _, tt := range tests {
+		if got := allowedOrigin(tt.origin); got != tt.want {
+			t.Errorf("allowedOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestRequireRoleLockout(t *testing.T) {
+	app := NewApp()
+	fiberApp := fiber.New()
+	fiberApp.Get("/control", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	request := func(header, secret string) int {
+		req := httptest.NewRequest("GET", "/control", nil)
+		if header != "" {
+			req.Header.Set(header, secret)
+		}
+		resp, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+	token := app.GetOperatorToken()
+
+	tests := []struct {
+		name   string
+		header string
+		secret string
+		want   int
+	}{
+		{"no key", "", "", 401},
+		{"bearer", "Authorization", "Bearer " + token, 200},
+		{"operator token header", "X-Operator-Token", token, 200},
+		{"bad bearer 1", "Authorization", "Bearer 0000", 401},
+		{"bad bearer 2", "Authorization", "Bearer 0001", 401},
+		{"bad header 3", "X-Operator-Token", "0002", 401},
+		{"bad header 4", "X-Operator-Token", "0003", 401},
+		{"bad header 5", "X-Operator-Token", "0004", 401},
+		{"locked out, even with the right key", "Authorization", "Bearer " + token, 429},
+	}
+	for _, tt := range tests {
+		if got := request(tt.header, tt.secret); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Keys in the URL aren't accepted.
+	req := httptest.NewRequest("GET", "/control?token="+token, nil)
+	app.loginAttempts = make(map[string]*loginAttempts)
+	resp, err := fiberApp.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 401 {
+		t.Errorf("?token= status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestLoginAttemptsBounded(t *testing.T) {
+	app := NewApp()
+	start := time.Now()
+	for i := 0; i < maxTrackedAddresses+50; i++ {
+		app.checkSecret("wrong", fmt.Sprintf("10.0.%d.%d", i/256, i%256), start)
+	}
+	if len(app.loginAttempts) > maxTrackedAddresses {
+		t.Errorf("tracking %d addresses, want at most %d", len(app.loginAttempts), maxTrackedAddresses)
+	}
+	// Failures expire once the window has passed.
+	app.checkSecret("wrong", "10.9.9.9", start.Add(loginAttemptWindow+time.Minute))
+	if len(app.loginAttempts) != 1 {
+		t.Errorf("tracking %d addresses after the window, want 1", len(app.loginAttempts))
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	app := NewApp()
+	token, err := app.CreateAccessKey("Scoreboard", roleViewer, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pin, err := app.CreateAccessKey("Track", roleOperator, "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenID, pinID := app.accessKeys[0].ID, app.accessKeys[1].ID
+	if !strings.HasPrefix(token, tokenID+".") {
+		t.Errorf("generated token %q doesn't start with its ID %q", token, tokenID)
+	}
+	// A key saved by an earlier version: unprefixed, with an unsalted hash of "87654321".
+	app.accessKeys = append(app.accessKeys, &AccessKey{
+		ID:   "legacy",
+		Role: roleAdmin,
+		Hash: "e24df920078c3dd4e7e8d2442f00e5c9ab2a231bb3918d65cc50906e49ecaef4",
+	})
+
+	tests := []struct {
+		name      string
+		secret    string
+		wantRole  string
+		wantKeyID string
+	}{
+		{"operator token", app.GetOperatorToken(), roleAdmin, ""},
+		{"generated token", token, roleViewer, tokenID},
+		{"PIN", pin, roleOperator, pinID},
+		{"legacy key", "87654321", roleAdmin, "legacy"},
+		{"wrong secret for a prefixed ID", tokenID + ".0000000000000000", "", ""},
+		{"unknown ID", "0000000000000000." + strings.SplitN(token, ".", 2)[1], "", ""},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, keyID := app.authenticate(tt.secret)
+			if role != tt.wantRole || keyID != tt.wantKeyID {
+				t.Errorf("authenticate() = %q, %q, want %q, %q", role, keyID, tt.wantRole, tt.wantKeyID)
+			}
+		})
+	}
+	if hash := app.accessKeys[2].Hash; !strings.Contains(hash, "$") {
+		t.Errorf("legacy key hash %q wasn't replaced with a salted one", hash)
+	}
+	if !secretMatches("87654321", app.accessKeys[2].Hash) {
+		t.Error("legacy key no longer matches its secret after rehashing")
+	}
+}
+
+func TestRequestSourceUsesRequireRole(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	app := NewApp()
+	token, err := app.CreateAccessKey("Scoreboard", roleOperator, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var source string
+	fiberApp := fiber.New()
+	fiberApp.Get("/control", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		// Replace the key so that checking it again would fail.
+		app.mu.Lock()
+		hash := app.accessKeys[0].Hash
+		app.accessKeys[0].Hash = hashSecret("something else")
+		app.mu.Unlock()
+		source = app.requestSource(c)
+		app.mu.Lock()
+		app.accessKeys[0].Hash = hash
+		app.mu.Unlock()
+		return c.SendStatus(200)
+	})
+	req := httptest.NewRequest("GET", "/control", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := fiberApp.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !strings.HasSuffix(source, `(key "Scoreboard")`) {
+		t.Errorf("requestSource() = %q, want the Scoreboard key", source)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultChannel is the display channel used by screens that don't ask for one.
+const defaultChannel = "default"
+
+// validChannelName restricts channel names to something that is safe in a URL.
+var validChannelName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// newDisplayState returns the display state a new install starts with.
+func newDisplayState() *DisplayState {
+	return &DisplayState{
+		Mode:         "lif",
+		ActiveText:   "",
+		ImageBase64:  "",
+		RotationMode: "scroll",
+		LayoutTheme:  "classic",
+		ShowBib:      true,
+	}
+}
+
+// normaliseChannel lowercases a channel name and maps an empty name to the default channel.
+func normaliseChannel(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return defaultChannel
+	}
+	return name
+}
+
+// channelStateLocked returns the state of a channel, falling back to the default
+// channel if it doesn't exist. The caller must hold a.mu.
+func (a *App) channelStateLocked(name string) *DisplayState {
+	if state, ok := a.channels[normaliseChannel(name)]; ok {
+		return state
+	}
+	return a.channels[defaultChannel]
+}
+
+// updateChannel applies fn to the state of an existing channel, records the change
+// in the audit journal against source and schedules a settings save.
+func (a *App) updateChannel(source string, name string, fn func(state *DisplayState)) error {
+	name = normaliseChannel(name)
+	a.mu.Lock()
+	state, ok := a.channels[name]
+	if !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("no display channel named %q", name)
+	}
+	fn(state)
+	a.mu.Unlock()
+	a.auditDisplays(source)
+	a.scheduleSave()
+	return nil
+}
+
+// applyDisplayStateUpdate merges a display state posted by a control panel into state.
+// Rotation mode and theme are only changed when provided, and the screensaver image
+// only when keepImage is false (see resolveDisplayImage). Slideshows are set separately.
+func applyDisplayStateUpdate(state *DisplayState, update DisplayState, keepImage bool) {
+	state.Mode = update.Mode
+	state.ActiveText = update.ActiveText
+	if keepImage {
+		state.ImageID = update.ImageID
+	}
+	if update.RotationMode != "" {
+		state.RotationMode = update.RotationMode
+	}
+	if update.LayoutTheme != "" {
+		state.LayoutTheme = update.LayoutTheme
+	}
+	state.ShowBib = update.ShowBib
+	state.CurrentLIF = update.CurrentLIF
+}
+
+// GetChannels returns the names of the display channels, default first.
+func (a *App) GetChannels() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	names := make([]string, 0, len(a.channels))
+	for name := range a.channels {
+		if name != defaultChannel {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{defaultChannel}, names...)
+}
+
+// CreateChannel adds a display channel, starting from a copy of the default channel's state.
+func (a *App) CreateChannel(name string) error {
+	name = normaliseChannel(name)
+	if !validChannelName.MatchString(name) {
+		return fmt.Errorf("invalid channel name %q: use lowercase letters, digits and hyphens", name)
+	}
+	a.mu.Lock()
+	if _, exists := a.channels[name]; exists {
+		a.mu.Unlock()
+		return fmt.Errorf("display channel %q already exists", name)
+	}
+	state := *a.channels[defaultChannel]
+	a.channels[name] = &state
+	a.mu.Unlock()
+	displayLog.Info("Display channel created", "channel", name)
+	a.scheduleSave()
+	return nil
+}
+
+// DeleteChannel removes a display channel. Screens on it fall back to the default channel.
+func (a *App) DeleteChannel(name string) error {
+	name = normaliseChannel(name)
+	if name == defaultChannel {
+		return fmt.Errorf("the default channel can't be deleted")
+	}
+	a.mu.Lock()
+	if _, exists := a.channels[name]; !exists {
+		a.mu.Unlock()
+		return fmt.Errorf("no display channel named %q", name)
+	}
+	delete(a.channels, name)
+	a.mu.Unlock()
+	displayLog.Info("Display channel deleted", "channel", name)
+	a.scheduleSave()
+	return nil
+}
+
+// GetChannelDisplayState returns a copy of a channel's display state. Unknown
+// channels return the default channel's state.
+func (a *App) GetChannelDisplayState(name string) *DisplayState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	state := *a.channelStateLocked(name)
+	return &state
+}
+
+// SetChannelDisplayState updates a channel's display state from a control panel.
+func (a *App) SetChannelDisplayState(name string, update DisplayState) error {
+	return a.setChannelDisplayState(sourceDesktop, name, update)
+}
+
+// setChannelDisplayState is SetChannelDisplayState, audited against source.
+func (a *App) setChannelDisplayState(source string, name string, update DisplayState) error {
+	keepImage := a.resolveDisplayImage(&update)
+	err := a.updateChannel(source, name, func(state *DisplayState) {
+		applyDisplayStateUpdate(state, update, keepImage)
+	})
+	if err == nil {
+		displayLog.Debug("Display state updated", "channel", normaliseChannel(name), "mode", update.Mode)
+	}
+	return err
+}
+
+// registerChannelRoutes adds the display channel endpoints to the Fiber server.
+func registerChannelRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/channels", func(c *fiber.Ctx) error {
+		return c.JSON(app.GetChannels())
+	})
+	fiberApp.Get("/channels/:name", func(c *fiber.Ctx) error {
+		return c.JSON(app.GetChannelDisplayState(c.Params("name")))
+	})
+	fiberApp.Post("/channels", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.CreateChannel(req.Name); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Delete("/channels/:name", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.DeleteChannel(c.Params("name")); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
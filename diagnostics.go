@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of row the parsers report on.
+const (
+	issueFields = "fields" // Too few fields to be a competitor row
+	issueDNS    = "dns"    // DNS or empty place, left out of the results
+	issueStatus = "status" // DQ or DNF, kept with the place cleared
+	issueNoTime = "no-time"
+	issueTime   = "time" // Time that couldn't be parsed
+)
+
+// RowIssue is a row the parser skipped or changed.
+type RowIssue struct {
+	Row     int      `json:"row"` // 1-based, as shown in a text editor
+	Kind    string   `json:"kind"`
+	Reason  string   `json:"reason"`
+	Skipped bool     `json:"skipped"` // The row is missing from the results
+	Raw     []string `json:"raw"`
+}
+
+// ParseReport describes how a result file was parsed.
+type ParseReport struct {
+	FileName    string     `json:"fileName"`
+	Format      string     `json:"format"`  // 'lif', 'res' or 'txt'
+	Charset     string     `json:"charset"` // As detected, empty if detection failed
+	Rows        int        `json:"rows"`
+	Competitors int        `json:"competitors"`
+	Issues      []RowIssue `json:"issues"`
+}
+
+// note records a row the parser changed but kept.
+func (r *ParseReport) note(index int, kind string, reason string, row []string) {
+	r.Issues = append(r.Issues, RowIssue{Row: index + 1, Kind: kind, Reason: reason, Raw: row})
+}
+
+// skip records a row the parser left out.
+func (r *ParseReport) skip(index int, kind string, reason string, row []string) {
+	r.Issues = append(r.Issues, RowIssue{Row: index + 1, Kind: kind, Reason: reason, Skipped: true, Raw: row})
+}
+
+// warnings returns the skipped rows an operator should know about. DNS entries
+// are left out on purpose, so they aren't warnings.
+func (r *ParseReport) warnings() []RowIssue {
+	var warnings []RowIssue
+	for _, issue := range r.Issues {
+		if issue.Skipped && issue.Kind != issueDNS {
+			warnings = append(warnings, issue)
+		}
+	}
+	return warnings
+}
+
+// skippedRows returns how many rows were left out of the results.
+func (r *ParseReport) skippedRows() int {
+	skipped := 0
+	for _, issue := range r.Issues {
+		if issue.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// FileDiagnostics is a result file that has parse warnings or failed to parse.
+type FileDiagnostics struct {
+	FileName     string     `json:"fileName"`
+	Charset      string     `json:"charset"`
+	Error        string     `json:"error"` // Why the file couldn't be parsed at all, if it couldn't
+	Warnings     []RowIssue `json:"warnings"`
+	ModifiedTime int64      `json:"modifiedTime"`
+}
+
+// GetDiagnostics parses every result file in the monitored directory and returns
+// the ones with skipped rows or that failed to parse, most recently changed first.
+func (a *App) GetDiagnostics() ([]FileDiagnostics, error) {
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return nil, fmt.Errorf("no directory selected")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics := []FileDiagnostics{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isResultFile(entry.Name()) {
+			continue
+		}
+		_, report, err := parseFileWithReport(filepath.Join(dir, entry.Name()))
+		file := FileDiagnostics{
+			FileName: entry.Name(),
+			Charset:  report.Charset,
+			Warnings: report.warnings(),
+		}
+		if err != nil {
+			file.Error = err.Error()
+		} else if len(file.Warnings) == 0 {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			file.ModifiedTime = info.ModTime().Unix()
+		}
+		diagnostics = append(diagnostics, file)
+	}
+	sort.Slice(diagnostics, func(i, j int) bool {
+		return diagnostics[i].ModifiedTime > diagnostics[j].ModifiedTime
+	})
+	return diagnostics, nil
+}
+
+// registerDiagnosticsRoutes adds the parse diagnostics endpoint to the Fiber server.
+func registerDiagnosticsRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/diagnostics", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		diagnostics, err := app.GetDiagnostics()
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(diagnostics)
+	})
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// displayOfflineAfter is how long a display can go without a heartbeat before it is shown offline.
+	displayOfflineAfter = 15 * time.Second
+	// displayForgetAfter is how long an offline display stays in the registry.
+	displayForgetAfter = 12 * time.Hour
+)
+
+// DisplayCommand is a remote control instruction delivered to a display with its next heartbeat.
+type DisplayCommand struct {
+	Action string `json:"action"` // 'reload', 'identify', 'rename' or 'channel'
+	Value  string `json:"value"`  // New name or channel for 'rename' and 'channel', the name to show for 'identify'
+}
+
+// ConnectedDisplay is a web display that has registered with the server.
+type ConnectedDisplay struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Channel       string `json:"channel"`
+	Resolution    string `json:"resolution"`
+	View          string `json:"view"` // Page the display is showing, e.g. '/results'
+	Address       string `json:"address"`
+	LastHeartbeat int64  `json:"lastHeartbeat"` // Unix time of the last heartbeat
+	Online        bool   `json:"online"`
+
+	commands []DisplayCommand
+}
+
+// displayHeartbeat is the body a display posts to /displays/heartbeat.
+type displayHeartbeat struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Channel    string `json:"channel"`
+	Resolution string `json:"resolution"`
+	View       string `json:"view"`
+}
+
+// recordHeartbeat registers or refreshes a display and returns the commands queued for it.
+// A display without an ID is given a new one.
+func (a *App) recordHeartbeat(hb displayHeartbeat, address string) (string, []DisplayCommand) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	id := strings.TrimSpace(hb.ID)
+	if id == "" {
+		id = randomToken()
+	}
+	display, ok := a.displays[id]
+	if !ok {
+		display = &ConnectedDisplay{ID: id}
+		a.displays[id] = display
+		displayLog.Info("Display registered", "id", id, "name", hb.Name, "address", address)
+	}
+	display.Name = hb.Name
+	if display.Name == "" {
+		short := id
+		if len(short) > 4 {
+			short = short[:4]
+		}
+		display.Name = "Display " + short
+	}
+	display.Channel = normaliseChannel(hb.Channel)
+	display.Resolution = hb.Resolution
+	display.View = hb.View
+	display.Address = address
+	display.LastHeartbeat = time.Now().Unix()
+
+	commands := display.commands
+	display.commands = nil
+	// Identify shows the name the server lists the display under, which is the
+	// assigned 'Display xxxx' name when the display hasn't been given one. A rename
+	// delivered in the same heartbeat applies before a later identify.
+	name := display.Name
+	for i, command := range commands {
+		switch command.Action {
+		case "rename":
+			name = command.Value
+		case "identify":
+			commands[i].Value = name
+		}
+	}
+	return id, commands
+}
+
+// GetDisplays returns the registered displays with their online status, sorted by name.
+func (a *App) GetDisplays() []ConnectedDisplay {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	result := make([]ConnectedDisplay, 0, len(a.displays))
+	for id, display := range a.displays {
+		last := time.Unix(display.LastHeartbeat, 0)
+		if now.Sub(last) > displayForgetAfter {
+			delete(a.displays, id)
+			continue
+		}
+		display.Online = now.Sub(last) <= displayOfflineAfter
+		entry := *display
+		entry.commands = nil
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// queueDisplayCommand queues a command for delivery with the display's next heartbeat.
+func (a *App) queueDisplayCommand(id string, command DisplayCommand) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	display, ok := a.displays[id]
+	if !ok {
+		return fmt.Errorf("no display with id %s", id)
+	}
+	display.commands = append(display.commands, command)
+	displayLog.Info("Display command queued", "id", id, "action", command.Action, "value", command.Value)
+	return nil
+}
+
+// ReloadDisplay makes a display reload its page.
+func (a *App) ReloadDisplay(id string) error {
+	return a.queueDisplayCommand(id, DisplayCommand{Action: "reload"})
+}
+
+// IdentifyDisplay makes a display flash its name on screen.
+func (a *App) IdentifyDisplay(id string) error {
+	return a.queueDisplayCommand(id, DisplayCommand{Action: "identify"})
+}
+
+// RenameDisplay changes the name a display reports.
+func (a *App) RenameDisplay(id string, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return a.queueDisplayCommand(id, DisplayCommand{Action: "rename", Value: name})
+}
+
+// AssignDisplayChannel moves a display to another display channel.
+func (a *App) AssignDisplayChannel(id string, channel string) error {
+	channel = normaliseChannel(channel)
+	a.mu.Lock()
+	_, exists := a.channels[channel]
+	a.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("no display channel named %q", channel)
+	}
+	return a.queueDisplayCommand(id, DisplayCommand{Action: "channel", Value: channel})
+}
+
+// registerDisplayRoutes adds the connected display endpoints to the Fiber server.
+func registerDisplayRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Post("/displays/heartbeat", func(c *fiber.Ctx) error {
+		var hb displayHeartbeat
+		if err := c.BodyParser(&hb); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		id, commands := app.recordHeartbeat(hb, c.IP())
+		if commands == nil {
+			commands = []DisplayCommand{}
+		}
+		return c.JSON(map[string]interface{}{"id": id, "commands": commands})
+	})
+	fiberApp.Get("/displays", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetDisplays())
+	})
+	fiberApp.Post("/displays/:id/command", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var command DisplayCommand
+		if err := c.BodyParser(&command); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		id := c.Params("id")
+		var err error
+		switch command.Action {
+		case "reload":
+			err = app.ReloadDisplay(id)
+		case "identify":
+			err = app.IdentifyDisplay(id)
+		case "rename":
+			err = app.RenameDisplay(id, command.Value)
+		case "channel":
+			err = app.AssignDisplayChannel(id, command.Value)
+		default:
+			err = fmt.Errorf("unknown display command %q", command.Action)
+		}
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestIdentifyShowsListedName(t *testing.T) {
+	app := NewApp()
+	id, _ := app.recordHeartbeat(displayHeartbeat{}, "10.0.0.5")
+	want := "Display " + id[:4]
+
+	if err := app.IdentifyDisplay(id); err != nil {
+		t.Fatal(err)
+	}
+	_, commands := app.recordHeartbeat(displayHeartbeat{ID: id}, "10.0.0.5")
+	if len(commands) != 1 || commands[0].Action != "identify" || commands[0].Value != want {
+		t.Fatalf("commands = %+v, want identify with %q", commands, want)
+	}
+
+	// A rename delivered with the identify is the name shown.
+	if err := app.RenameDisplay(id, "Finish Line"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.IdentifyDisplay(id); err != nil {
+		t.Fatal(err)
+	}
+	_, commands = app.recordHeartbeat(displayHeartbeat{ID: id}, "10.0.0.5")
+	if len(commands) != 2 || commands[1].Value != "Finish Line" {
+		t.Errorf("commands = %+v, want identify with %q", commands, "Finish Line")
+	}
+}
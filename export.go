@@ -0,0 +1,234 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// resultColumns are the columns of a results export, one row per competitor.
+var resultColumns = []string{
+	"Session", "Event", "Round", "Heat", "Wind", "Place", "Bib", "First Name", "Last Name",
+	"Club", "Club Acronym", "Time", "Status", "File", "Saved",
+}
+
+// resultRows flattens the results into one row per competitor, oldest event first,
+// with the competitors who didn't start after the others.
+// session filters them like filterResults, with empty meaning all results, and
+// event keeps only events whose name contains it, ignoring case.
+func (a *App) resultRows(session string, event string) ([][]string, error) {
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, err
+	}
+	meet := a.GetMeet()
+	now := time.Now()
+	sessions := meet.sessions(results, now)
+	results, err = meet.filterResults(results, session, now)
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]*LifData(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ModifiedTime < sorted[j].ModifiedTime })
+	acronyms := a.clubAcronyms()
+	event = strings.ToLower(strings.TrimSpace(event))
+
+	rows := [][]string{}
+	for _, data := range sorted {
+		if event != "" && !strings.Contains(strings.ToLower(data.EventName), event) {
+			continue
+		}
+		sessionName := ""
+		if len(sessions) > 0 {
+			sessionName = sessions[sessionIndex(sessions, data.ModifiedTime)].Name
+		}
+		saved := time.Unix(data.ModifiedTime, 0).Format("2006-01-02 15:04:05")
+		competitors := append([]Competitor(nil), data.Competitors...)
+		for _, c := range data.DNS {
+			c.Time = "DNS"
+			competitors = append(competitors, c)
+		}
+		for _, c := range competitors {
+			status := competitorStatus(c)
+			resultTime := c.Time
+			if status != "" {
+				resultTime = ""
+			}
+			rows = append(rows, []string{
+				sessionName, data.EventName, data.Round, data.Heat, data.Wind, c.Place, c.ID, c.FirstName, c.LastName,
+				c.Affiliation, clubAcronym(c.Affiliation, acronyms), resultTime, status, data.FileName, saved,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// writeResultsCSV writes exported result rows as CSV with a header row.
+func writeResultsCSV(out io.Writer, rows [][]string) error {
+	return csv.NewWriter(out).WriteAll(append([][]string{resultColumns}, rows...))
+}
+
+// xlsxColumn returns the spreadsheet name of a zero-based column, e.g. 'A' or 'AB'.
+func xlsxColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxEscape escapes text for a cell of a spreadsheet.
+func xlsxEscape(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+// writeResultsXLSX writes exported result rows as an Excel workbook with a single
+// sheet, a bold header row frozen at the top, and every cell as text so that bibs
+// and times keep their leading zeros.
+func writeResultsXLSX(out io.Writer, rows [][]string) error {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+<sheetData>
+`)
+	writeRow := func(number int, values []string, style int) {
+		fmt.Fprintf(&sheet, `<row r="%d">`, number)
+		for i, value := range values {
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr" s="%d"><is><t xml:space="preserve">%s</t></is></c>`,
+				xlsxColumn(i), number, style, xlsxEscape(value))
+		}
+		sheet.WriteString("</row>\n")
+	}
+	writeRow(1, resultColumns, 1)
+	for i, row := range rows {
+		writeRow(i+2, row, 0)
+	}
+	sheet.WriteString("</sheetData>\n</worksheet>\n")
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>
+`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>
+`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Results" sheetId="1" r:id="rId1"/></sheets>
+</workbook>
+`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>
+`},
+		{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="49" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="49" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/></cellXfs>
+</styleSheet>
+`},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+	archive := zip.NewWriter(out)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// writeResults writes exported result rows as 'csv' or 'xlsx'.
+func writeResults(out io.Writer, format string, rows [][]string) error {
+	if format == "xlsx" {
+		return writeResultsXLSX(out, rows)
+	}
+	return writeResultsCSV(out, rows)
+}
+
+// ExportResults asks where to save every result as a spreadsheet and writes it as
+// 'csv' or 'xlsx', one row per competitor. session and event filter the results
+// as in resultRows. It returns the path written, or an empty string if cancelled.
+func (a *App) ExportResults(format string, session string, event string) (string, error) {
+	if format != "csv" && format != "xlsx" {
+		return "", fmt.Errorf("unknown format %q, use csv or xlsx", format)
+	}
+	rows, err := a.resultRows(session, event)
+	if err != nil {
+		return "", err
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Results",
+		DefaultFilename: "results." + format,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to export results: %v", err)
+	}
+	defer f.Close()
+	if err := writeResults(f, format, rows); err != nil {
+		return "", fmt.Errorf("failed to export results: %v", err)
+	}
+	resultsLog.Info("Results exported", "path", path, "rows", len(rows))
+	return path, nil
+}
+
+// registerExportRoutes adds the results spreadsheet endpoints to the Fiber server.
+// Like /all-lif they need no key. ?session= and ?event= filter the results.
+func registerExportRoutes(fiberApp *fiber.App, app *App) {
+	for _, format := range []string{"csv", "xlsx"} {
+		fiberApp.Get("/export/results."+format, func(c *fiber.Ctx) error {
+			rows, err := app.resultRows(c.Query("session"), c.Query("event"))
+			var notFound notFoundError
+			if errors.As(err, &notFound) {
+				return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+			}
+			if err != nil {
+				return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+			}
+			if format == "xlsx" {
+				c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			} else {
+				c.Set("Content-Type", "text/csv")
+			}
+			c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="results.%s"`, format))
+			return writeResults(c.Response().BodyWriter(), format, rows)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestXlsxColumn(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{14, "O"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := xlsxColumn(tt.index); got != tt.want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestResultRows(t *testing.T) {
+	dir := t.TempDir()
+	saturday := time.Date(2026, 6, 13, 10, 0, 0, 0, time.Local)
+	sunday := time.Date(2026, 6, 14, 10, 0, 0, 0, time.Local)
+	hundred := writeLif(t, dir, "a.lif", "Women 100m",
+		"1,101,4,Smith,Alice,Kingston,12.01",
+		"DQ,102,5,Jones,Bella,Sutton,",
+		"DNS,103,6,Brown,Cara,Herne Hill,")
+	twoHundred := writeLif(t, dir, "b.lif", "Women 200m", "1,101,4,Smith,Alice,Kingston,25.10")
+	os.Chtimes(hundred, saturday, saturday)
+	os.Chtimes(twoHundred, sunday, sunday)
+	app := newTestApp(t, dir, false)
+	app.meet = &Meet{Sessions: []MeetSession{
+		{Name: "Saturday", Date: "2026-06-13", Start: "09:00"},
+		{Name: "Sunday", Date: "2026-06-14", Start: "09:00"},
+	}}
+
+	// Place, bib, time and status of each row.
+	type row struct{ event, place, bib, time, status string }
+	tests := []struct {
+		name    string
+		session string
+		event   string
+		want    []row
+	}{
+		{"everything", "", "", []row{
+			{"Women 100m", "1", "101", "12.01", ""},
+			{"Women 100m", "", "102", "", "DQ"},
+			{"Women 100m", "", "103", "", "DNS"},
+			{"Women 200m", "1", "101", "25.10", ""},
+		}},
+		{"one session", "2026-06-14T09:00", "", []row{
+			{"Women 200m", "1", "101", "25.10", ""},
+		}},
+		{"event name", "", "100M", []row{
+			{"Women 100m", "1", "101", "12.01", ""},
+			{"Women 100m", "", "102", "", "DQ"},
+			{"Women 100m", "", "103", "", "DNS"},
+		}},
+		{"session and event", "2026-06-13T09:00", "200m", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := app.resultRows(tt.session, tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []row
+			for _, r := range rows {
+				if len(r) != len(resultColumns) {
+					t.Fatalf("row has %d columns, want %d", len(r), len(resultColumns))
+				}
+				got = append(got, row{r[1], r[5], r[6], r[11], r[12]})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultRows(%q, %q) = %v, want %v", tt.session, tt.event, got, tt.want)
+			}
+		})
+	}
+	if _, err := app.resultRows("2026-06-15T09:00", ""); err == nil {
+		t.Error("resultRows() with an unknown session succeeded")
+	}
+}
+
+func TestExportRoute(t *testing.T) {
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "Women 100m", "1,101,4,Smith,Alice,Kingston,12.01")
+	app := newTestApp(t, dir, false)
+	fiberApp := fiber.New()
+	registerExportRoutes(fiberApp, app)
+
+	tests := []struct {
+		name  string
+		query string
+		dir   string
+		want  int
+	}{
+		{"all results", "", dir, 200},
+		{"unknown session", "?session=2020-01-01T09:00", dir, 404},
+		{"no directory", "", "", 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.monitoredDir = tt.dir
+			resp, err := fiberApp.Test(httptest.NewRequest("GET", "/export/results.csv"+tt.query, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteResultsCSVError(t *testing.T) {
+	// Even with no rows, the header is written and its failure reported.
+	if err := writeResultsCSV(failingWriter{}, nil); err == nil {
+		t.Error("writeResultsCSV() succeeded with a failing writer")
+	}
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Replication roles.
+const (
+	roleStandalone = "standalone" // No replication
+	rolePrimary    = "primary"    // Serves snapshots to a backup
+	roleBackup     = "backup"     // Mirrors a primary and takes over if it stops responding
+)
+
+const (
+	// replicationInterval is how often a backup fetches a snapshot from the primary.
+	// Each fetch doubles as the primary's heartbeat.
+	replicationInterval = 2 * time.Second
+	// failoverAfter is how many missed heartbeats in a row make a backup take over.
+	failoverAfter = 3
+	// mediaFetchTimeout is how long a backup allows for downloading one media item.
+	mediaFetchTimeout = 10 * time.Minute
+)
+
+// ReplicationSettings configures primary/backup failover.
+type ReplicationSettings struct {
+	Role       string `json:"role"`       // 'standalone', 'primary' or 'backup'
+	PrimaryURL string `json:"primaryUrl"` // Backup only, e.g. 'http://192.168.1.10:3000'
+	Key        string `json:"key"`        // Backup only, an operator access key created on the primary
+}
+
+// ReplicationStatus reports the state of replication for the desktop app.
+type ReplicationStatus struct {
+	Role           string `json:"role"`
+	Active         bool   `json:"active"`         // Backup only: has taken over from the primary
+	LastSync       int64  `json:"lastSync"`       // Backup only: Unix time of the last snapshot
+	LastError      string `json:"lastError"`      // Backup only: why the last fetch failed
+	BackupAddress  string `json:"backupAddress"`  // Primary only: address of the backup
+	BackupLastSeen int64  `json:"backupLastSeen"` // Primary only: Unix time of the backup's last fetch
+}
+
+// replicationSnapshot is the state a backup mirrors from the primary.
+type replicationSnapshot struct {
+	InstanceID string                   `json:"instanceId"`
+	Hostname   string                   `json:"hostname"` // mDNS hostname the backup advertises when it takes over
+	Results    []*LifData               `json:"results"`
+	Latest     *LifData                 `json:"latest"`
+	Overrides  []ResultOverride         `json:"overrides"`
+	Channels   map[string]*DisplayState `json:"channels"`
+	Playlists  []Playlist               `json:"playlists"`
+	Media      []MediaItem              `json:"media"` // The backup downloads any it doesn't have from /media/<id>
+}
+
+// replicationState is the backup's view of the primary.
+type replicationState struct {
+	results   []*LifData // mirrored results, nil until the first snapshot
+	hostname  string
+	active    bool
+	failures  int
+	lastSync  time.Time
+	lastError string
+	// syncingMedia is set while missing media is being downloaded from the primary.
+	syncingMedia bool
+	// Primary side: the backup that last fetched a snapshot.
+	backupAddress  string
+	backupLastSeen time.Time
+}
+
+// GetReplicationSettings returns the failover settings.
+func (a *App) GetReplicationSettings() ReplicationSettings {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	settings := a.replication
+	if settings.Role == "" {
+		settings.Role = roleStandalone
+	}
+	return settings
+}
+
+// SetReplicationSettings changes the failover role. A backup needs the primary's
+// URL and an operator key created on the primary.
+func (a *App) SetReplicationSettings(settings ReplicationSettings) error {
+	settings.PrimaryURL = strings.TrimRight(strings.TrimSpace(settings.PrimaryURL), "/")
+	settings.Key = strings.TrimSpace(settings.Key)
+	switch settings.Role {
+	case "", roleStandalone:
+		settings = ReplicationSettings{Role: roleStandalone}
+	case rolePrimary:
+		settings.PrimaryURL, settings.Key = "", ""
+	case roleBackup:
+		if !strings.HasPrefix(settings.PrimaryURL, "http://") && !strings.HasPrefix(settings.PrimaryURL, "https://") {
+			return fmt.Errorf("primary URL must start with http:// or https://")
+		}
+		if settings.Key == "" {
+			return fmt.Errorf("an operator key from the primary is required")
+		}
+	default:
+		return fmt.Errorf("unknown replication role %q", settings.Role)
+	}
+	a.mu.Lock()
+	wasActive := a.replica.active
+	wasBackup := a.replication.Role == roleBackup
+	a.replication = settings
+	a.replica = replicationState{}
+	if wasBackup && settings.Role != roleBackup {
+		// Drop the primary's corrections mirrored by applySnapshot.
+		a.overrides = nil
+	}
+	a.mu.Unlock()
+	if wasBackup && settings.Role != roleBackup {
+		a.initOverrides()
+	}
+	if wasActive {
+		// Stop advertising the primary's hostname.
+		a.stopMDNS()
+		a.startMDNS()
+	}
+	replicationLog.Info("Replication role set", "role", settings.Role)
+	a.scheduleSave()
+	return nil
+}
+
+// GetReplicationStatus returns the current replication state.
+func (a *App) GetReplicationStatus() ReplicationStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	status := ReplicationStatus{
+		Role:          a.replication.Role,
+		Active:        a.replica.active,
+		LastError:     a.replica.lastError,
+		BackupAddress: a.replica.backupAddress,
+	}
+	if status.Role == "" {
+		status.Role = roleStandalone
+	}
+	if !a.replica.lastSync.IsZero() {
+		status.LastSync = a.replica.lastSync.Unix()
+	}
+	if !a.replica.backupLastSeen.IsZero() {
+		status.BackupLastSeen = a.replica.backupLastSeen.Unix()
+	}
+	return status
+}
+
+// replicatedResults returns the results mirrored from the primary, or nil if this
+// instance isn't a backup or hasn't received a snapshot yet.
+func (a *App) replicatedResults() []*LifData {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.replication.Role != roleBackup {
+		return nil
+	}
+	return a.replica.results
+}
+
+// replicationSnapshot captures the state a backup mirrors.
+func (a *App) replicationSnapshot() *replicationSnapshot {
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		results = []*LifData{}
+	}
+	a.mu.Lock()
+	latest := a.latestData
+	a.mu.Unlock()
+	return &replicationSnapshot{
+		InstanceID: a.instanceID,
+		Hostname:   a.GetMDNSSettings().Hostname,
+		Results:    results,
+		Latest:     latest,
+		Overrides:  a.GetOverrides(),
+		Channels:   a.currentChannels(),
+		Playlists:  a.GetPlaylists(),
+		Media:      a.GetMedia(),
+	}
+}
+
+// currentChannels returns a copy of every display channel's state.
+func (a *App) currentChannels() map[string]*DisplayState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	channels := make(map[string]*DisplayState, len(a.channels))
+	for name, channel := range a.channels {
+		state := *channel
+		channels[name] = &state
+	}
+	return channels
+}
+
+// applySnapshot mirrors a snapshot from the primary.
+func (a *App) applySnapshot(snapshot *replicationSnapshot) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.replica.results = snapshot.Results
+	a.replica.hostname = snapshot.Hostname
+	a.latestData = snapshot.Latest
+	a.overrides = snapshot.Overrides
+	if len(snapshot.Channels) > 0 {
+		a.channels = snapshot.Channels
+		if _, ok := a.channels[defaultChannel]; !ok {
+			a.channels[defaultChannel] = newDisplayState()
+		}
+	}
+	a.playlists = make(map[string]*Playlist, len(snapshot.Playlists))
+	for i := range snapshot.Playlists {
+		playlist := snapshot.Playlists[i]
+		a.playlists[playlist.Name] = &playlist
+	}
+}
+
+// missingMedia returns the media items the backup doesn't have yet.
+func (a *App) missingMedia(items []MediaItem) []MediaItem {
+	var missing []MediaItem
+	for _, item := range items {
+		if _, _, err := a.mediaPath(item.ID); err != nil {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// syncMedia downloads media items from the primary into the backup's media library,
+// so screens showing an image or slideshow by ID keep working after a takeover.
+func (a *App) syncMedia(settings ReplicationSettings, items []MediaItem) {
+	for _, item := range items {
+		if err := a.fetchMedia(settings, item); err != nil {
+			replicationLog.Error("Failed to copy media from primary", "id", item.ID, "error", err)
+			continue
+		}
+		replicationLog.Info("Copied media from primary", "id", item.ID, "name", item.Name)
+	}
+}
+
+// fetchMedia downloads one media item from the primary.
+func (a *App) fetchMedia(settings ReplicationSettings, item MediaItem) error {
+	client := &http.Client{Timeout: mediaFetchTimeout}
+	resp, err := client.Get(settings.PrimaryURL + "/media/" + url.PathEscape(item.ID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("primary returned %s", resp.Status)
+	}
+	added, err := a.addMedia(item.Name, resp.Body)
+	if err != nil {
+		return err
+	}
+	if added.ID != item.ID {
+		// IDs are content hashes, so this is a different file.
+		a.DeleteMedia(added.ID)
+		return fmt.Errorf("downloaded file has id %s", added.ID)
+	}
+	return nil
+}
+
+// replicationRequest sends a request to the primary with the backup's key.
+func replicationRequest(method string, url string, key string, body interface{}) (*http.Response, error) {
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: replicationInterval}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("primary returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// fetchSnapshot fetches a snapshot from the primary.
+func fetchSnapshot(settings ReplicationSettings) (*replicationSnapshot, error) {
+	resp, err := replicationRequest("GET", settings.PrimaryURL+"/replication/snapshot", settings.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var snapshot replicationSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+	return &snapshot, nil
+}
+
+// takeOver makes a backup serve in place of the primary, advertising its hostname.
+func (a *App) takeOver() {
+	a.mu.Lock()
+	a.replica.active = true
+	hostname := a.replica.hostname
+	a.mu.Unlock()
+	replicationLog.Warn("Primary stopped responding, taking over", "hostname", hostname)
+	a.emitEvent("failover", a.GetReplicationStatus())
+	a.stopMDNS()
+	a.startMDNS()
+}
+
+// handBack returns control to the primary once it is responding again, sending
+// it any display changes made on the backup while it was in charge.
+func (a *App) handBack(settings ReplicationSettings) {
+	channels := a.currentChannels()
+	resp, err := replicationRequest("POST", settings.PrimaryURL+"/replication/handback", settings.Key,
+		map[string]interface{}{"channels": channels})
+	if err != nil {
+		replicationLog.Error("Failed to send display state back to primary", "error", err)
+	} else {
+		resp.Body.Close()
+	}
+	a.mu.Lock()
+	a.replica.active = false
+	a.replica.failures = 0
+	a.replica.lastError = ""
+	a.mu.Unlock()
+	replicationLog.Info("Primary is back, handing over")
+	a.emitEvent("failover", a.GetReplicationStatus())
+	a.stopMDNS()
+	a.startMDNS()
+}
+
+// replicate runs one replication cycle on a backup.
+func (a *App) replicate() {
+	settings := a.GetReplicationSettings()
+	if settings.Role != roleBackup {
+		return
+	}
+	snapshot, err := fetchSnapshot(settings)
+	if err != nil {
+		a.mu.Lock()
+		a.replica.failures++
+		a.replica.lastError = err.Error()
+		takeOver := a.replica.failures == failoverAfter && a.replica.results != nil && !a.replica.active
+		a.mu.Unlock()
+		if takeOver {
+			a.takeOver()
+		}
+		return
+	}
+	if snapshot.InstanceID == a.instanceID {
+		a.mu.Lock()
+		a.replica.lastError = "the primary URL points at this instance"
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Lock()
+	active := a.replica.active
+	a.mu.Unlock()
+	if active {
+		a.handBack(settings)
+		// The primary now has the backup's display state; mirror it on the next cycle.
+		return
+	}
+	a.applySnapshot(snapshot)
+	a.auditDisplays(sourceReplication)
+	missing := a.missingMedia(snapshot.Media)
+	a.mu.Lock()
+	a.replica.failures = 0
+	a.replica.lastError = ""
+	a.replica.lastSync = time.Now()
+	syncMedia := len(missing) > 0 && !a.replica.syncingMedia
+	if syncMedia {
+		a.replica.syncingMedia = true
+	}
+	a.mu.Unlock()
+	if syncMedia {
+		// Large videos can take longer than a replication cycle.
+		go func() {
+			a.syncMedia(settings, missing)
+			a.mu.Lock()
+			a.replica.syncingMedia = false
+			a.mu.Unlock()
+		}()
+	}
+}
+
+// runReplication mirrors the primary while this instance is a backup.
+func (a *App) runReplication() {
+	ticker := time.NewTicker(replicationInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.replicate()
+	}
+}
+
+// registerReplicationRoutes adds the primary side of replication to the Fiber server.
+func registerReplicationRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/replication/snapshot", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if app.GetReplicationSettings().Role != rolePrimary {
+			return c.Status(409).JSON(map[string]interface{}{"error": "this instance is not a primary"})
+		}
+		app.mu.Lock()
+		app.replica.backupAddress = c.IP()
+		app.replica.backupLastSeen = time.Now()
+		app.mu.Unlock()
+		return c.JSON(app.replicationSnapshot())
+	})
+	fiberApp.Post("/replication/handback", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if app.GetReplicationSettings().Role != rolePrimary {
+			return c.Status(409).JSON(map[string]interface{}{"error": "this instance is not a primary"})
+		}
+		var req struct {
+			Channels map[string]*DisplayState `json:"channels"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		app.mu.Lock()
+		for name, state := range req.Channels {
+			if state != nil && (name == defaultChannel || validChannelName.MatchString(name)) {
+				app.channels[name] = state
+			}
+		}
+		app.mu.Unlock()
+		app.scheduleSave()
+		replicationLog.Info("Display state handed back", "address", c.IP())
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSyncMedia(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	primary := NewApp()
+	primary.settingsFile = t.TempDir() + "/settings.json"
+	sponsor, err := primary.addMedia("sponsor.png", bytes.NewReader(append(pngHeader, "sponsor"...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logo, err := primary.addMedia("logo.png", bytes.NewReader(append(pngHeader, "logo"...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, id := range []string{sponsor.ID, logo.ID} {
+		if files[id], _, err = primary.mediaPath(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/media/")
+		if id == logo.ID {
+			// A file that doesn't match its id isn't kept.
+			w.Write(append(pngHeader, "changed"...))
+			return
+		}
+		path, ok := files[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, path)
+	}))
+	defer server.Close()
+
+	// Backup with its own media folder.
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	backup := NewApp()
+	backup.settingsFile = t.TempDir() + "/settings.json"
+	missing := backup.missingMedia([]MediaItem{*sponsor, *logo, {ID: "gone", Name: "gone.png"}})
+	if len(missing) != 3 {
+		t.Fatalf("missingMedia() = %+v, want all three", missing)
+	}
+	backup.syncMedia(ReplicationSettings{Role: roleBackup, PrimaryURL: server.URL}, missing)
+
+	media := backup.GetMedia()
+	if len(media) != 1 || media[0].ID != sponsor.ID || media[0].Name != "sponsor.png" {
+		t.Errorf("backup media = %+v, want only sponsor.png", media)
+	}
+	if missing := backup.missingMedia([]MediaItem{*sponsor}); len(missing) != 0 {
+		t.Errorf("missingMedia() after sync = %+v, want none", missing)
+	}
+}
+
+func TestLeavingBackupRestoresOverrides(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := saveOverrides(dir, []ResultOverride{{Bib: "101", Status: "DQ"}}); err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, dir, false)
+	if err := app.SetReplicationSettings(ReplicationSettings{Role: roleBackup, PrimaryURL: "http://primary:3000", Key: "key"}); err != nil {
+		t.Fatal(err)
+	}
+	app.applySnapshot(&replicationSnapshot{Overrides: []ResultOverride{{Bib: "202", Hidden: true}}})
+	if got := app.GetOverrides(); len(got) != 1 || got[0].Bib != "202" {
+		t.Fatalf("overrides while backup = %+v, want the primary's", got)
+	}
+
+	if err := app.SetReplicationSettings(ReplicationSettings{Role: roleStandalone}); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.GetOverrides(); len(got) != 1 || got[0].Bib != "101" {
+		t.Errorf("overrides after leaving backup = %+v, want this machine's own", got)
+	}
+}
@@ -1,9 +1,10 @@
 import React, { useState, useEffect, useMemo, useRef } from 'react';
 import { Link, useNavigate } from 'react-router-dom';
-import { ChooseDirectory, EnterFullScreen, ExitFullScreen, GetWebInterfaceInfo, SaveGraphic } from "../wailsjs/go/main/App";
+import { ChooseDirectory, EnterFullScreen, ExitFullScreen, GetWebInterfaceInfo, SaveGraphic, GetMonitoredDirectory, GetDisplayState, GetOperatorToken, GetAccessKeys, CreateAccessKey, DeleteAccessKey, GetHTTPSEnabled, SetHTTPSEnabled, GetServerSettings, SetServerSettings, GetMDNSSettings, SetMDNSSettings, DiscoverInstances, GetReplicationSettings, SetReplicationSettings, GetReplicationStatus, GetDiagnostics, GetDebugLogs, GetLogLevel, SetLogLevel, ExportLogs, GetAuditLog, ExportAuditLog, GetMeet, SetMeet, GetSessions, ArchiveMeet, OpenArchive, CloseArchive, GetArchiveInfo, ExportSite, SaveResultSheet, SaveSessionResultSheets, ExportResults, ExportRankings } from "../wailsjs/go/main/App";
 import { THEMES, getColumnWidths, shortenClub } from './themes';
 import polyfieldLogo from './polyfield-logo.png';
 import SocialGraphic from './SocialGraphic';
+import { desktopServerUrl, refreshServerUrl } from './serverUrl';
 
 // Fixed dimensions for the default table container.
 const DEFAULT_TABLE_HEIGHT = 192; // in pixels
@@ -140,6 +141,32 @@ function App() {
   const [showTheme, setShowTheme] = useState(false);
   const [showBibs, setShowBibs] = useState(false);
   const [showStats, setShowStats] = useState(false);
+  const [showAccessKeys, setShowAccessKeys] = useState(false);
+  const [accessKeys, setAccessKeys] = useState([]);
+  const [newKey, setNewKey] = useState({ name: '', role: 'operator', pin: '' });
+  const [createdKey, setCreatedKey] = useState('');
+  const [httpsEnabled, setHttpsEnabled] = useState(false);
+  const [serverSettings, setServerSettings] = useState({ listenAddress: '0.0.0.0', port: 3000 });
+  const [showInstances, setShowInstances] = useState(false);
+  const [mdnsSettings, setMdnsSettings] = useState({ instanceName: '', hostname: '' });
+  const [instances, setInstances] = useState([]);
+  const [discovering, setDiscovering] = useState(false);
+  const [showFailover, setShowFailover] = useState(false);
+  const [replication, setReplication] = useState({ role: 'standalone', primaryUrl: '', key: '' });
+  const [replicationStatus, setReplicationStatus] = useState(null);
+  const [showDiagnostics, setShowDiagnostics] = useState(false);
+  const [diagnostics, setDiagnostics] = useState([]);
+  const [showLogs, setShowLogs] = useState(false);
+  const [logLevel, setLogLevelState] = useState('info');
+  const [showAudit, setShowAudit] = useState(false);
+  const [showMeet, setShowMeet] = useState(false);
+  const [meet, setMeet] = useState({ name: '', venue: '', startDate: '', endDate: '', sessions: [], sessionBreaks: [], displayFilter: 'all' });
+  const [archiveInfo, setArchiveInfo] = useState(null);
+  const [archiveMessage, setArchiveMessage] = useState('');
+  const [rankingReport, setRankingReport] = useState(null);
+  const [exportFilter, setExportFilter] = useState({ session: '', event: '' });
+  const [sessions, setSessions] = useState([]);
+  const [auditEntries, setAuditEntries] = useState([]);
 
   // === DEBUG STATE ===
   const [debugLog, setDebugLog] = useState([]);
@@ -212,7 +239,7 @@ function App() {
       // Desktop app: use local server. Web browser: use relative URLs
       const hostname = window.location.hostname;
       const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-      const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+      const baseUrl = isDesktop ? desktopServerUrl : '';
       const fullUrl = `${baseUrl}/latest-lif`;
       const response = await fetch(fullUrl);
       if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
@@ -247,7 +274,7 @@ function App() {
     try {
       const hostname = window.location.hostname;
       const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-      const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+      const baseUrl = isDesktop ? desktopServerUrl : '';
       const response = await fetch(`${baseUrl}/all-lif`);
       if (!response.ok) return;
       const data = await response.json();
@@ -264,7 +291,7 @@ function App() {
     try {
       const hostname = window.location.hostname;
       const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-      const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+      const baseUrl = isDesktop ? desktopServerUrl : '';
       const payload = {
         mode: mode,
         activeText: text || '',
@@ -292,9 +319,11 @@ function App() {
         rotationMode: payload.rotationMode,
         currentLIF: payload.currentLIF?.eventName || 'none'
       });
+      // Control endpoints need a key; the desktop app uses its own admin token
+      const token = await GetOperatorToken();
       await fetch(`${baseUrl}/display-state`, {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', 'X-Operator-Token': token },
         body: JSON.stringify(payload)
       });
     } catch (error) {
@@ -643,6 +672,41 @@ function App() {
     startup();
   }, []);
 
+  // Restore the folder and display settings saved by the previous run.
+  // Display state is only synced to the server once this has completed,
+  // so the defaults above don't overwrite the restored state.
+  const settingsRestoredRef = useRef(false);
+  useEffect(() => {
+    const restore = async () => {
+      try {
+        await refreshServerUrl();
+        const dir = await GetMonitoredDirectory();
+        if (dir) {
+          setSelectedDir(dir);
+          addDebugLog(`Resumed monitoring: ${dir}`);
+        }
+        const state = await GetDisplayState();
+        if (state) {
+          if (state.rotationMode) setRotationMode(state.rotationMode);
+          if (state.layoutTheme) setLayoutTheme(state.layoutTheme);
+          setShowBib(state.showBib !== false);
+          if (state.mode === 'text') {
+            setDisplayMode('text');
+            setActiveText(state.activeText || '');
+            setInputText(state.activeText || '');
+          } else if (state.mode === 'screensaver') {
+            setDisplayMode('screensaver');
+          }
+          if (state.imageId) setLinkedImage(`${desktopServerUrl}/media/${state.imageId}`);
+        }
+      } catch (error) {
+        addDebugLog("Failed to restore saved settings");
+      }
+      settingsRestoredRef.current = true;
+    };
+    restore();
+  }, []);
+
   // Window resize effect
   useEffect(() => {
     const handleResize = () => setWindowSize({ width: window.innerWidth, height: window.innerHeight });
@@ -655,7 +719,7 @@ function App() {
     try {
       const hostname = window.location.hostname;
       const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-      const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+      const baseUrl = isDesktop ? desktopServerUrl : '';
       const response = await fetch(`${baseUrl}/display-state`);
       if (!response.ok) return;
       const state = await response.json();
@@ -703,7 +767,7 @@ function App() {
         } else if (state.mode === 'screensaver') {
           console.log('[LAN] Server mode is screensaver');
           setDisplayMode('screensaver');
-          setLinkedImage(state.imageBase64 || '');
+          setLinkedImage(state.imageId ? `${baseUrl}/media/${state.imageId}` : '');
           addDebugLog('Display mode synced: Screensaver');
         }
       }
@@ -717,7 +781,7 @@ function App() {
     try {
       const hostname = window.location.hostname;
       const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-      const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+      const baseUrl = isDesktop ? desktopServerUrl : '';
       const response = await fetch(`${baseUrl}/club-acronyms`);
       if (!response.ok) return;
       const data = await response.json();
@@ -762,12 +826,278 @@ function App() {
     };
   }, []); // No dependencies - pure polling
 
+  // === ACCESS KEYS ===
+  const refreshAccessKeys = async () => {
+    try {
+      setAccessKeys(await GetAccessKeys() || []);
+    } catch (err) {
+      addDebugLog(`Failed to load access keys: ${err}`);
+    }
+  };
+
+  const createAccessKey = async () => {
+    try {
+      const secret = await CreateAccessKey(newKey.name, newKey.role, newKey.pin);
+      setCreatedKey(secret);
+      setNewKey({ name: '', role: newKey.role, pin: '' });
+      setError('');
+      refreshAccessKeys();
+    } catch (err) {
+      setError(`Error creating access key: ${err}`);
+    }
+  };
+
+  const deleteAccessKey = async (id) => {
+    try {
+      await DeleteAccessKey(id);
+      refreshAccessKeys();
+    } catch (err) {
+      setError(`Error deleting access key: ${err}`);
+    }
+  };
+
+  useEffect(() => {
+    if (showAccessKeys) refreshAccessKeys();
+  }, [showAccessKeys]);
+
+  const toggleHttps = async () => {
+    try {
+      await SetHTTPSEnabled(!httpsEnabled);
+      setHttpsEnabled(!httpsEnabled);
+      setWebInterfaceInfo(await GetWebInterfaceInfo());
+      setError('');
+    } catch (err) {
+      setError(`Error changing HTTPS: ${err}`);
+    }
+  };
+
+  // Restarts the web server on the new address and port; displays reconnect on their next poll
+  const applyServerSettings = async () => {
+    try {
+      await SetServerSettings(serverSettings.listenAddress, Number(serverSettings.port));
+      await refreshServerUrl();
+      setWebInterfaceInfo(await GetWebInterfaceInfo());
+      setError('');
+      addDebugLog(`Web server restarted on ${serverSettings.listenAddress}:${serverSettings.port}`);
+    } catch (err) {
+      setError(`Error changing server settings: ${err}`);
+    }
+  };
+
+  // === LAN DISCOVERY ===
+  const discoverInstances = async () => {
+    setDiscovering(true);
+    try {
+      setInstances(await DiscoverInstances() || []);
+    } catch (err) {
+      addDebugLog(`Discovery failed: ${err}`);
+    }
+    setDiscovering(false);
+  };
+
+  const saveMdnsSettings = async () => {
+    try {
+      await SetMDNSSettings(mdnsSettings.instanceName, mdnsSettings.hostname);
+      setMdnsSettings(await GetMDNSSettings());
+      setError('');
+    } catch (err) {
+      setError(`Error changing network name: ${err}`);
+    }
+  };
+
+  useEffect(() => {
+    if (!showInstances) return;
+    GetMDNSSettings().then(setMdnsSettings).catch(() => {});
+    discoverInstances();
+  }, [showInstances]);
+
+  // === MEET & SESSIONS ===
+  const saveMeet = async (changes = {}) => {
+    const updated = { ...meet, ...changes };
+    try {
+      await SetMeet(updated);
+      setMeet(updated);
+      setSessions(await GetSessions() || []);
+      setError('');
+    } catch (err) {
+      setError(`Error saving meet: ${err}`);
+    }
+  };
+
+  const updateMeetSession = (index, field, value) => {
+    const updated = meet.sessions.map((s, i) => (i === index ? { ...s, [field]: value } : s));
+    setMeet({ ...meet, sessions: updated });
+  };
+
+  const openSession = (id) => {
+    const hn = window.location.hostname;
+    const isDesktop = hn === '' || hn === 'wails.localhost' || window.location.protocol === 'wails:';
+    const base = isDesktop ? desktopServerUrl : window.location.origin;
+    window.open(`${base}/results?session=${encodeURIComponent(id)}`, '_blank');
+  };
+
+  const archiveMeet = async () => {
+    try {
+      const path = await ArchiveMeet();
+      if (path) setArchiveMessage(`Archive saved to ${path}`);
+      setError('');
+    } catch (err) {
+      setError(`Error archiving meet: ${err}`);
+    }
+  };
+
+  const openArchive = async () => {
+    try {
+      const info = await OpenArchive();
+      if (info) {
+        setArchiveInfo(info);
+        setArchiveMessage('');
+      }
+      setError('');
+    } catch (err) {
+      setError(`Error opening archive: ${err}`);
+    }
+  };
+
+  const exportSite = async () => {
+    try {
+      const dir = await ExportSite();
+      if (dir) setArchiveMessage(`Results site written to ${dir}`);
+      setError('');
+    } catch (err) {
+      setError(`Error exporting results site: ${err}`);
+    }
+  };
+
+  const saveResultSheet = async (session) => {
+    try {
+      const path = session
+        ? await SaveSessionResultSheets(session)
+        : await SaveResultSheet(currentLifData?.fileName || '');
+      setArchiveMessage(`Result sheet saved to ${path}`);
+      setError('');
+    } catch (err) {
+      setError(`Error saving result sheet: ${err}`);
+    }
+  };
+
+  const exportResults = async (format) => {
+    try {
+      const path = await ExportResults(format, exportFilter.session, exportFilter.event);
+      if (path) setArchiveMessage(`Results exported to ${path}`);
+      setError('');
+    } catch (err) {
+      setError(`Error exporting results: ${err}`);
+    }
+  };
+
+  const exportRankings = async (format) => {
+    try {
+      const report = await ExportRankings(format, exportFilter.session);
+      if (report) {
+        setRankingReport(report);
+        setArchiveMessage(`${report.rows} results exported to ${report.path}`);
+      }
+      setError('');
+    } catch (err) {
+      setError(`Error exporting rankings: ${err}`);
+    }
+  };
+
+  const closeArchive = async () => {
+    await CloseArchive();
+    setArchiveInfo(null);
+  };
+
+  useEffect(() => {
+    if (!showMeet) return;
+    GetArchiveInfo().then(setArchiveInfo).catch(() => {});
+    GetMeet().then((m) => setMeet({ ...m, sessions: m.sessions || [], sessionBreaks: m.sessionBreaks || [] })).catch(() => {});
+    const refresh = () => GetSessions().then((s) => setSessions(s || [])).catch(() => setSessions([]));
+    refresh();
+    const interval = setInterval(refresh, 10000);
+    return () => clearInterval(interval);
+  }, [showMeet, selectedDir, archiveInfo]);
+
+  // === FAILOVER ===
+  const saveReplication = async () => {
+    try {
+      await SetReplicationSettings(replication);
+      setReplicationStatus(await GetReplicationStatus());
+      setError('');
+    } catch (err) {
+      setError(`Error changing failover settings: ${err}`);
+    }
+  };
+
+  useEffect(() => {
+    if (!showFailover) return;
+    GetReplicationSettings().then(setReplication).catch(() => {});
+    const refresh = () => GetReplicationStatus().then(setReplicationStatus).catch(() => {});
+    refresh();
+    const interval = setInterval(refresh, 2000);
+    return () => clearInterval(interval);
+  }, [showFailover]);
+
+  // === PARSE PROBLEMS ===
+  // Polled even while collapsed, so the heading shows the number of problem files.
+  useEffect(() => {
+    const refresh = () => GetDiagnostics().then(setDiagnostics).catch(() => setDiagnostics([]));
+    refresh();
+    const interval = setInterval(refresh, 5000);
+    return () => clearInterval(interval);
+  }, [selectedDir]);
+
+  // === LOGS ===
+  const changeLogLevel = async (level) => {
+    try {
+      await SetLogLevel(level);
+      setLogLevelState(level);
+      setError('');
+    } catch (err) {
+      setError(`Error changing log level: ${err}`);
+    }
+  };
+
+  const exportLogs = async () => {
+    try {
+      const path = await ExportLogs();
+      if (path) addDebugLog(`Logs exported: ${path}`);
+    } catch (err) {
+      setError(`Error exporting logs: ${err}`);
+    }
+  };
+
+  useEffect(() => {
+    if (!showLogs) return;
+    GetLogLevel().then(setLogLevelState).catch(() => {});
+  }, [showLogs]);
+
+  const exportAuditLog = async (format) => {
+    try {
+      const path = await ExportAuditLog(format);
+      if (path) addDebugLog(`Audit trail exported: ${path}`);
+    } catch (err) {
+      setError(`Error exporting audit trail: ${err}`);
+    }
+  };
+
+  useEffect(() => {
+    if (!showAudit) return;
+    const fetchAudit = () => GetAuditLog({ limit: 50 }).then((entries) => setAuditEntries(entries || [])).catch(() => setAuditEntries([]));
+    fetchAudit();
+    const interval = setInterval(fetchAudit, 5000);
+    return () => clearInterval(interval);
+  }, [showAudit]);
+
   // Web interface info effect
   useEffect(() => {
     async function fetchWebInterfaceInfo() {
       try {
         const info = await GetWebInterfaceInfo();
         setWebInterfaceInfo(info);
+        setHttpsEnabled(await GetHTTPSEnabled());
+        setServerSettings(await GetServerSettings());
         addDebugLog("Web interface info loaded");
       } catch (error) {
         addDebugLog("Failed to load web interface info");
@@ -805,7 +1135,7 @@ function App() {
   useEffect(() => {
     const hostname = window.location.hostname;
     const isDesktopApp = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-    if (isDesktopApp && rotationMode) {
+    if (isDesktopApp && rotationMode && settingsRestoredRef.current) {
       syncDisplayState(displayMode, activeText, linkedImage, rotationMode);
       addDebugLog(`Syncing rotation mode to server: ${rotationMode}`);
     }
@@ -815,7 +1145,7 @@ function App() {
   useEffect(() => {
     const hostname = window.location.hostname;
     const isDesktopApp = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-    if (isDesktopApp && layoutTheme) {
+    if (isDesktopApp && layoutTheme && settingsRestoredRef.current) {
       syncDisplayState(displayMode, activeText, linkedImage, rotationMode);
     }
   }, [layoutTheme]);
@@ -824,7 +1154,7 @@ function App() {
   useEffect(() => {
     const hostname = window.location.hostname;
     const isDesktopApp = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-    if (isDesktopApp) {
+    if (isDesktopApp && settingsRestoredRef.current) {
       syncDisplayState(displayMode, activeText, linkedImage, rotationMode);
     }
   }, [showBib]);
@@ -1444,7 +1774,7 @@ function App() {
                   <button onClick={() => {
                     const hn = window.location.hostname;
                     const isDesktop = hn === '' || hn === 'wails.localhost' || window.location.protocol === 'wails:';
-                    window.open(isDesktop ? 'http://127.0.0.1:3000/athlete' : `${window.location.origin}/athlete`, '_blank');
+                    window.open(isDesktop ? `${desktopServerUrl}/athlete` : `${window.location.origin}/athlete`, '_blank');
                   }} style={{
                     flex: 1, backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
                     borderRadius: '6px', padding: '10px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.9rem',
@@ -1456,11 +1786,211 @@ function App() {
                     borderRadius: '6px', padding: '10px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.9rem',
                   }}>Social Graphic</button>
                 </div>
+                <div style={{ display: 'flex', alignItems: 'center', gap: '10px', marginTop: '10px' }}>
+                  <button onClick={toggleHttps} style={{
+                    backgroundColor: httpsEnabled ? '#2e7d32' : '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '8px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>HTTPS: {httpsEnabled ? 'On' : 'Off'}</button>
+                  {httpsEnabled && (
+                    <span style={{ color: '#a0b4c8', fontSize: '0.8rem' }}>
+                      Install the certificate from /ca.crt on each tablet to trust the HTTPS address.
+                    </span>
+                  )}
+                </div>
+                <div style={{ display: 'flex', alignItems: 'center', gap: '6px', marginTop: '10px' }}>
+                  <span style={{ color: '#a0b4c8', fontSize: '0.8rem' }}>Listen on</span>
+                  <input
+                    value={serverSettings.listenAddress}
+                    onChange={(e) => setServerSettings({ ...serverSettings, listenAddress: e.target.value })}
+                    style={{ flex: 2, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <input
+                    type="number"
+                    value={serverSettings.port}
+                    onChange={(e) => setServerSettings({ ...serverSettings, port: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <button onClick={applyServerSettings} style={{
+                    backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Apply</button>
+                </div>
+              </>
+            )}
+          </div>
+
+          {/* 7. Meet & Sessions - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowMeet(!showMeet)}
+              style={{ color: '#ffffff', marginBottom: showMeet ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showMeet ? '▾' : '▸'} Meet &amp; Sessions
+            </h6>
+            {showMeet && (
+              <>
+                {archiveInfo && (
+                  <div style={{ backgroundColor: '#4a3800', border: '1px solid #FFD700', borderRadius: '6px', padding: '8px', marginBottom: '8px', fontSize: '0.85rem', color: '#ffffff' }}>
+                    Browsing the archive of <strong>{archiveInfo.meet.name || archiveInfo.sourceDir}</strong> ({archiveInfo.results} results, archived {new Date(archiveInfo.created * 1000).toLocaleString()}). Displays show the archive, read-only, until it is closed.
+                  </div>
+                )}
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  Results are grouped into sessions by the time they were saved. Without a schedule each day is a session, optionally split at the given times of day.
+                </p>
+                <div style={{ display: 'flex', gap: '6px', marginBottom: '6px' }}>
+                  <input placeholder="Meet name" value={meet.name} onChange={(e) => setMeet({ ...meet, name: e.target.value })} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                  <input placeholder="Venue" value={meet.venue} onChange={(e) => setMeet({ ...meet, venue: e.target.value })} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                </div>
+                <div style={{ display: 'flex', alignItems: 'center', gap: '6px', marginBottom: '6px' }}>
+                  <input type="date" value={meet.startDate} onChange={(e) => setMeet({ ...meet, startDate: e.target.value })} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                  <span style={{ color: '#a0b4c8', fontSize: '0.8rem' }}>to</span>
+                  <input type="date" value={meet.endDate} onChange={(e) => setMeet({ ...meet, endDate: e.target.value })} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                </div>
+                <div style={{ display: 'flex', alignItems: 'center', gap: '6px', marginBottom: '6px' }}>
+                  <span style={{ color: '#a0b4c8', fontSize: '0.8rem' }}>Split days at</span>
+                  <input
+                    placeholder="e.g. 13:00, 17:30"
+                    value={meet.sessionBreaks.join(', ')}
+                    onChange={(e) => setMeet({ ...meet, sessionBreaks: e.target.value.split(',').map((t) => t.trim()).filter((t) => t) })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                </div>
+                <div style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '4px' }}>Schedule</div>
+                {meet.sessions.map((s, i) => (
+                  <div key={i} style={{ display: 'flex', gap: '6px', marginBottom: '4px' }}>
+                    <input placeholder="Session name" value={s.name} onChange={(e) => updateMeetSession(i, 'name', e.target.value)} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                    <input type="date" value={s.date} onChange={(e) => updateMeetSession(i, 'date', e.target.value)} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                    <input type="time" value={s.start} onChange={(e) => updateMeetSession(i, 'start', e.target.value)} style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }} />
+                    <button onClick={() => setMeet({ ...meet, sessions: meet.sessions.filter((_, j) => j !== i) })} style={{
+                      backgroundColor: '#b71c1c', color: '#ffffff', border: 'none',
+                      borderRadius: '6px', padding: '4px 8px', cursor: 'pointer', fontSize: '0.8rem',
+                    }}>Remove</button>
+                  </div>
+                ))}
+                <div style={{ display: 'flex', gap: '8px', marginBottom: '8px' }}>
+                  <button onClick={() => setMeet({ ...meet, sessions: [...meet.sessions, { name: '', date: meet.startDate || '', start: '09:00' }] })} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Add Session</button>
+                  <button onClick={() => saveMeet()} style={{
+                    backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Save Meet</button>
+                </div>
+                <div style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '4px' }}>Displays show</div>
+                <SegmentedControl
+                  options={[
+                    { value: 'all', label: 'All Results' },
+                    { value: 'today', label: 'Today' },
+                    { value: 'current', label: 'This Session' },
+                  ]}
+                  selected={meet.displayFilter}
+                  onChange={(value) => saveMeet({ displayFilter: value })}
+                />
+                <div style={{ color: '#a0b4c8', fontSize: '0.8rem', margin: '8px 0 4px' }}>Sessions</div>
+                {sessions.slice().reverse().map((s) => (
+                  <div key={s.id} style={{ display: 'flex', alignItems: 'center', gap: '8px', marginBottom: '4px', fontSize: '0.85rem', color: '#e0e0e0' }}>
+                    <span style={{ flex: 1, fontWeight: s.current ? 'bold' : 'normal' }}>{s.name}{s.current ? ' (now)' : ''}</span>
+                    <span style={{ color: '#7a9ab8' }}>{s.results} results</span>
+                    <button onClick={() => openSession(s.id)} style={{
+                      backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                      borderRadius: '6px', padding: '4px 8px', cursor: 'pointer', fontSize: '0.8rem',
+                    }}>View</button>
+                    <button onClick={() => saveResultSheet(s.id)} disabled={s.results === 0} style={{
+                      backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                      borderRadius: '6px', padding: '4px 8px', cursor: s.results ? 'pointer' : 'default', fontSize: '0.8rem', opacity: s.results ? 1 : 0.5,
+                    }}>PDF</button>
+                  </div>
+                ))}
+                <div style={{ color: '#a0b4c8', fontSize: '0.8rem', margin: '8px 0 4px' }}>Archive</div>
+                <div style={{ display: 'flex', gap: '8px' }}>
+                  {!archiveInfo && (
+                    <button onClick={archiveMeet} disabled={!selectedDir} style={{
+                      backgroundColor: '#2e7d32', color: '#ffffff', border: 'none',
+                      borderRadius: '6px', padding: '6px 12px', cursor: selectedDir ? 'pointer' : 'default', fontSize: '0.85rem', opacity: selectedDir ? 1 : 0.5,
+                    }}>Archive Meet</button>
+                  )}
+                  <button onClick={openArchive} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Open Archive</button>
+                  {archiveInfo && (
+                    <button onClick={closeArchive} style={{
+                      backgroundColor: '#b71c1c', color: '#ffffff', border: 'none',
+                      borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                    }}>Close Archive</button>
+                  )}
+                </div>
+                <div style={{ color: '#a0b4c8', fontSize: '0.8rem', margin: '8px 0 4px' }}>Publish</div>
+                <div style={{ display: 'flex', gap: '8px' }}>
+                  <button onClick={exportSite} style={{
+                    backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Export Results Site</button>
+                  <button onClick={() => saveResultSheet('')} disabled={!currentLifData} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: currentLifData ? 'pointer' : 'default', fontSize: '0.85rem', opacity: currentLifData ? 1 : 0.5,
+                  }}>Result Sheet (Current Event)</button>
+                </div>
+                <div style={{ display: 'flex', gap: '6px', marginTop: '6px' }}>
+                  <select
+                    value={exportFilter.session}
+                    onChange={(e) => setExportFilter({ ...exportFilter, session: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px' }}
+                  >
+                    <option value="">All results</option>
+                    <option value="today">Today</option>
+                    <option value="current">This session</option>
+                    {sessions.map((s) => (
+                      <option key={s.id} value={s.id}>{s.name}</option>
+                    ))}
+                  </select>
+                  <input
+                    placeholder="Event name contains (CSV and Excel)"
+                    value={exportFilter.event}
+                    onChange={(e) => setExportFilter({ ...exportFilter, event: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                </div>
+                <div style={{ display: 'flex', gap: '8px', marginTop: '6px' }}>
+                  <button onClick={() => exportResults('csv')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Export CSV</button>
+                  <button onClick={() => exportResults('xlsx')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Export Excel</button>
+                  <button onClick={() => exportRankings('powerof10')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Power of 10</button>
+                  <button onClick={() => exportRankings('opentrack')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>OpenTrack</button>
+                </div>
+                {archiveMessage && (
+                  <div style={{ color: '#81c784', fontSize: '0.8rem', marginTop: '4px' }}>{archiveMessage}</div>
+                )}
+                {rankingReport && rankingReport.issues.length > 0 && (
+                  <div style={{ marginTop: '6px', fontSize: '0.8rem' }}>
+                    <div style={{ color: '#ff8a80', marginBottom: '2px' }}>
+                      Not exported ({rankingReport.issues.length}):
+                      <span onClick={() => setRankingReport(null)} style={{ color: '#7a9ab8', cursor: 'pointer', marginLeft: '8px' }}>dismiss</span>
+                    </div>
+                    {rankingReport.issues.map((issue, i) => (
+                      <div key={i} style={{ color: '#e0e0e0', marginBottom: '2px' }}>
+                        <span style={{ color: '#7a9ab8' }}>{issue.event || issue.file}{issue.athlete ? ` – ${issue.athlete}` : ''}:</span> {issue.problem}
+                      </div>
+                    ))}
+                  </div>
+                )}
               </>
             )}
           </div>
 
-          {/* 7. Competition Stats - collapsible */}
+          {/* 8. Competition Stats - collapsible */}
           <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
             <h6
               onClick={() => setShowStats(!showStats)}
@@ -1493,6 +2023,293 @@ function App() {
             )}
           </div>
 
+          {/* 9. Nearby Instances - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowInstances(!showInstances)}
+              style={{ color: '#ffffff', marginBottom: showInstances ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showInstances ? '▾' : '▸'} Nearby Instances
+            </h6>
+            {showInstances && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  How this laptop is advertised on the network. Give each laptop at a venue its own hostname.
+                </p>
+                <div style={{ display: 'flex', gap: '6px', marginBottom: '10px' }}>
+                  <input
+                    placeholder="Instance name"
+                    value={mdnsSettings.instanceName}
+                    onChange={(e) => setMdnsSettings({ ...mdnsSettings, instanceName: e.target.value })}
+                    style={{ flex: 2, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <input
+                    placeholder="track.local"
+                    value={mdnsSettings.hostname}
+                    onChange={(e) => setMdnsSettings({ ...mdnsSettings, hostname: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <button onClick={saveMdnsSettings} style={{
+                    backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Save</button>
+                </div>
+                {instances.length === 0 && !discovering && (
+                  <p style={{ color: '#7a9ab8', fontSize: '0.8rem' }}>No other instances found.</p>
+                )}
+                {instances.map((instance) => (
+                  <div key={instance.url} style={{ display: 'flex', alignItems: 'center', gap: '8px', marginBottom: '4px', fontSize: '0.85rem', color: '#e0e0e0' }}>
+                    <span style={{ flex: 2 }}>{instance.name}</span>
+                    <span style={{ flex: 2, color: '#7a9ab8' }}>{instance.meet || 'No meet'}</span>
+                    <span style={{ flex: 2, color: instance.hostnameConflict ? '#ff8a80' : '#64b5f6' }}>
+                      {instance.hostname}{instance.hostnameConflict ? ' (same hostname!)' : ''}
+                    </span>
+                    <button onClick={() => window.open(instance.url, '_blank')} style={{
+                      backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                      borderRadius: '4px', padding: '2px 8px', cursor: 'pointer', fontSize: '0.8rem',
+                    }}>Open</button>
+                  </div>
+                ))}
+                <button onClick={discoverInstances} disabled={discovering} style={{
+                  marginTop: '6px', backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                  borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                }}>{discovering ? 'Searching...' : 'Refresh'}</button>
+              </>
+            )}
+          </div>
+
+          {/* 10. Failover - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowFailover(!showFailover)}
+              style={{ color: '#ffffff', marginBottom: showFailover ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showFailover ? '▾' : '▸'} Failover
+            </h6>
+            {showFailover && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  A backup mirrors the primary and takes over its displays if the primary stops responding.
+                </p>
+                <SegmentedControl
+                  options={[
+                    { value: 'standalone', label: 'Standalone' },
+                    { value: 'primary', label: 'Primary' },
+                    { value: 'backup', label: 'Backup' },
+                  ]}
+                  selected={replication.role}
+                  onChange={(role) => setReplication({ ...replication, role })}
+                />
+                {replication.role === 'backup' && (
+                  <div style={{ display: 'flex', gap: '6px', marginTop: '8px' }}>
+                    <input
+                      placeholder="Primary URL, e.g. http://192.168.1.10:3000"
+                      value={replication.primaryUrl}
+                      onChange={(e) => setReplication({ ...replication, primaryUrl: e.target.value })}
+                      style={{ flex: 2, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                    />
+                    <input
+                      placeholder="Operator key"
+                      value={replication.key}
+                      onChange={(e) => setReplication({ ...replication, key: e.target.value })}
+                      style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                    />
+                  </div>
+                )}
+                <button onClick={saveReplication} style={{
+                  marginTop: '8px', backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                  borderRadius: '6px', padding: '6px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                }}>Save</button>
+                {replicationStatus && replicationStatus.role === 'backup' && (
+                  <p style={{ color: replicationStatus.active ? '#ff8a80' : '#a0b4c8', fontSize: '0.8rem', marginTop: '8px' }}>
+                    {replicationStatus.active
+                      ? 'Primary unreachable - this laptop is serving the displays.'
+                      : replicationStatus.lastSync
+                        ? `Mirroring primary, last sync ${new Date(replicationStatus.lastSync * 1000).toLocaleTimeString()}`
+                        : 'Waiting for the primary...'}
+                    {replicationStatus.lastError && ` (${replicationStatus.lastError})`}
+                  </p>
+                )}
+                {replicationStatus && replicationStatus.role === 'primary' && (
+                  <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginTop: '8px' }}>
+                    {replicationStatus.backupLastSeen
+                      ? `Backup ${replicationStatus.backupAddress} last synced ${new Date(replicationStatus.backupLastSeen * 1000).toLocaleTimeString()}`
+                      : 'No backup connected yet.'}
+                  </p>
+                )}
+              </>
+            )}
+          </div>
+
+          {/* 11. Parse Problems - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowDiagnostics(!showDiagnostics)}
+              style={{ color: diagnostics.length > 0 ? '#ff8a80' : '#ffffff', marginBottom: showDiagnostics ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showDiagnostics ? '▾' : '▸'} Parse Problems{diagnostics.length > 0 ? ` (${diagnostics.length})` : ''}
+            </h6>
+            {showDiagnostics && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  Result files that couldn't be read, or where athletes were left out because a row couldn't be parsed.
+                </p>
+                {diagnostics.length === 0 && (
+                  <p style={{ color: '#7a9ab8', fontSize: '0.8rem' }}>No problems found.</p>
+                )}
+                {diagnostics.map((file) => (
+                  <div key={file.fileName} style={{ marginBottom: '8px', fontSize: '0.85rem', color: '#e0e0e0' }}>
+                    <div style={{ fontWeight: 'bold' }}>
+                      {file.fileName}
+                      <span style={{ color: '#7a9ab8', fontWeight: 'normal' }}>{file.charset ? ` (${file.charset})` : ''}</span>
+                    </div>
+                    {file.error && (
+                      <div style={{ color: '#ff8a80' }}>Not shown: {file.error}</div>
+                    )}
+                    {(file.warnings || []).map((warning) => (
+                      <div key={warning.row} style={{ color: '#ffcc80', fontSize: '0.8rem' }}>
+                        Row {warning.row}: {warning.reason}
+                        <span style={{ color: '#7a9ab8' }}> - {warning.raw.join(' | ')}</span>
+                      </div>
+                    ))}
+                  </div>
+                ))}
+              </>
+            )}
+          </div>
+
+          {/* 12. Logs - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowLogs(!showLogs)}
+              style={{ color: '#ffffff', marginBottom: showLogs ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showLogs ? '▾' : '▸'} Logs
+            </h6>
+            {showLogs && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  Debug also logs every row read from every result file. Use it while investigating a problem, then switch back to Info.
+                </p>
+                <SegmentedControl
+                  options={[
+                    { value: 'debug', label: 'Debug' },
+                    { value: 'info', label: 'Info' },
+                    { value: 'warn', label: 'Warn' },
+                    { value: 'error', label: 'Error' },
+                  ]}
+                  selected={logLevel}
+                  onChange={changeLogLevel}
+                />
+                <button onClick={exportLogs} style={{
+                  marginTop: '8px', backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                  borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                }}>Export Logs for Support</button>
+              </>
+            )}
+          </div>
+
+          {/* 13. Audit Trail - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowAudit(!showAudit)}
+              style={{ color: '#ffffff', marginBottom: showAudit ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showAudit ? '▾' : '▸'} Audit Trail
+            </h6>
+            {showAudit && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  Every result file seen and every change to the screens, approvals and corrections, kept in polyfield-audit.jsonl in the results directory.
+                </p>
+                {auditEntries.length === 0 && (
+                  <p style={{ color: '#7a9ab8', fontSize: '0.8rem' }}>Nothing recorded yet.</p>
+                )}
+                <div style={{ maxHeight: '200px', overflowY: 'auto', marginBottom: '8px' }}>
+                  {auditEntries.map((entry, i) => (
+                    <div key={i} style={{ fontSize: '0.75rem', color: '#e0e0e0', marginBottom: '2px' }}>
+                      <span style={{ color: '#7a9ab8' }}>{new Date(entry.time).toLocaleTimeString()} </span>
+                      <span style={{ fontWeight: 'bold' }}>{entry.action}</span>
+                      {entry.channel ? ` [${entry.channel}]` : ''}
+                      {entry.file ? ` ${entry.file}` : ''}
+                      <span style={{ color: '#a0b4c8' }}> {entry.source}</span>
+                      <div style={{ color: '#7a9ab8' }}>{entry.detail}</div>
+                    </div>
+                  ))}
+                </div>
+                <div style={{ display: 'flex', gap: '8px' }}>
+                  <button onClick={() => exportAuditLog('csv')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Export CSV</button>
+                  <button onClick={() => exportAuditLog('json')} style={{
+                    backgroundColor: '#37474f', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Export JSON</button>
+                </div>
+              </>
+            )}
+          </div>
+
+          {/* 14. Access Keys - collapsible */}
+          <div style={{ marginBottom: '16px', paddingBottom: '16px', borderBottom: '1px solid #1a3050' }}>
+            <h6
+              onClick={() => setShowAccessKeys(!showAccessKeys)}
+              style={{ color: '#ffffff', marginBottom: showAccessKeys ? '8px' : 0, fontSize: '0.95rem', cursor: 'pointer', userSelect: 'none' }}
+            >
+              {showAccessKeys ? '▾' : '▸'} Access Keys
+            </h6>
+            {showAccessKeys && (
+              <>
+                <p style={{ color: '#a0b4c8', fontSize: '0.8rem', marginBottom: '8px' }}>
+                  PINs and tokens for web control panels. PINs need at least 8 characters; leave the PIN empty to generate a token.
+                </p>
+                {accessKeys.map((key) => (
+                  <div key={key.id} style={{ display: 'flex', alignItems: 'center', gap: '8px', marginBottom: '4px', fontSize: '0.85rem', color: '#e0e0e0' }}>
+                    <span style={{ flex: 1 }}>{key.name}</span>
+                    <span style={{ color: '#7a9ab8' }}>{key.role}</span>
+                    <button onClick={() => deleteAccessKey(key.id)} style={{
+                      backgroundColor: '#b71c1c', color: '#ffffff', border: 'none',
+                      borderRadius: '4px', padding: '2px 8px', cursor: 'pointer', fontSize: '0.8rem',
+                    }}>Revoke</button>
+                  </div>
+                ))}
+                <div style={{ display: 'flex', gap: '6px', marginTop: '8px' }}>
+                  <input
+                    placeholder="Name"
+                    value={newKey.name}
+                    onChange={(e) => setNewKey({ ...newKey, name: e.target.value })}
+                    style={{ flex: 2, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <select
+                    value={newKey.role}
+                    onChange={(e) => setNewKey({ ...newKey, role: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px' }}
+                  >
+                    <option value="viewer">Viewer</option>
+                    <option value="operator">Operator</option>
+                    <option value="admin">Admin</option>
+                  </select>
+                  <input
+                    placeholder="PIN (optional)"
+                    value={newKey.pin}
+                    onChange={(e) => setNewKey({ ...newKey, pin: e.target.value })}
+                    style={{ flex: 1, padding: '6px', borderRadius: '4px', border: '1px solid #1a3050' }}
+                  />
+                  <button onClick={createAccessKey} style={{
+                    backgroundColor: '#1565c0', color: '#ffffff', border: 'none',
+                    borderRadius: '6px', padding: '6px 12px', fontWeight: 'bold', cursor: 'pointer', fontSize: '0.85rem',
+                  }}>Add</button>
+                </div>
+                {createdKey && (
+                  <p style={{ color: '#FFD700', fontSize: '0.85rem', marginTop: '8px' }}>
+                    New key: <strong>{createdKey}</strong> - note it down, it won't be shown again.
+                  </p>
+                )}
+              </>
+            )}
+          </div>
+
         </div>
 
         {/* Footer */}
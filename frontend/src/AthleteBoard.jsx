@@ -1,4 +1,6 @@
 import React, { useState, useEffect, useMemo, useRef } from 'react';
+import { startDisplayHeartbeat } from './displayClient';
+import { desktopServerUrl } from './serverUrl';
 
 function AthleteBoard() {
   const [lifDataArray, setLifDataArray] = useState([]);
@@ -9,14 +11,20 @@ function AthleteBoard() {
   const searchRef = useRef(null);
   const inputRef = useRef(null);
 
+  // Register this kiosk with the server so the operator can see and control it
+  useEffect(() => startDisplayHeartbeat(), []);
+
   // Fetch all LIF data every 3 seconds (same pattern as Results.jsx)
   useEffect(() => {
     async function fetchData() {
       try {
         const hostname = window.location.hostname;
         const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-        const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
-        const response = await fetch(`${baseUrl}/all-lif`);
+        const baseUrl = isDesktop ? desktopServerUrl : '';
+        // Screens pick a session with ?session=today, current or a session ID; otherwise the meet's display filter applies
+        const session = new URLSearchParams(window.location.search).get('session');
+        const query = session ? `?session=${encodeURIComponent(session)}` : '';
+        const response = await fetch(`${baseUrl}/all-lif${query}`);
         if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
         const data = await response.json();
         setLifDataArray(data || []);
@@ -2,6 +2,8 @@ import React, { useState, useEffect, useMemo, useRef } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { GetAllLIFData, ChooseDirectory, EnterFullScreen, ExitFullScreen, GetWebInterfaceInfo } from '../wailsjs/go/main/App';
 import { THEMES, getColumnWidths, shortenClub } from './themes';
+import { startDisplayHeartbeat } from './displayClient';
+import { desktopServerUrl } from './serverUrl';
 
 function Results() {
   const navigate = useNavigate();
@@ -20,7 +22,7 @@ function Results() {
   const [isFullScreen, setIsFullScreen] = useState(false);
 
   // New state for view mode (multi-grid or full-screen-table)
-  const [viewMode, setViewMode] = useState('multi'); // 'multi' or 'fullscreen'
+  const [viewMode, setViewMode] = useState('multi'); // 'multi', 'fullscreen' or 'league'
   const [currentLIF, setCurrentLIF] = useState(null); // Current single event from desktop
   const [rotationMode, setRotationMode] = useState('scroll'); // Synced from desktop
   const [layoutTheme, setLayoutTheme] = useState('classic'); // Synced from desktop
@@ -28,7 +30,9 @@ function Results() {
   // Display mode synced from desktop (for text/screensaver overlays)
   const [syncedDisplayMode, setSyncedDisplayMode] = useState('lif'); // 'lif', 'text', or 'screensaver'
   const [syncedActiveText, setSyncedActiveText] = useState('');
-  const [syncedImageBase64, setSyncedImageBase64] = useState('');
+  const [syncedImageIds, setSyncedImageIds] = useState([]); // Media library IDs (one image, or a slideshow)
+  const [slideshowInterval, setSlideshowInterval] = useState(10);
+  const [slideIndex, setSlideIndex] = useState(0);
 
   // Custom club acronyms and bib toggle
   const [customAcronyms, setCustomAcronyms] = useState(null);
@@ -49,6 +53,12 @@ function Results() {
   const prevArrayLengthRef = useRef(0);
   const lifDataArrayRef = useRef(lifDataArray);
 
+  // Register this screen with the server so the operator can see and control it
+  useEffect(() => {
+    if (isDesktopApp) return undefined;
+    return startDisplayHeartbeat();
+  }, []);
+
   // Fetch all LIF data every 3 seconds using HTTP endpoint (works for both local and remote access)
   useEffect(() => {
     async function fetchData() {
@@ -56,8 +66,11 @@ function Results() {
         // Desktop app: use local server. Web browser: use relative URLs
         const hostname = window.location.hostname;
         const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-        const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
-        const response = await fetch(`${baseUrl}/all-lif`);
+        const baseUrl = isDesktop ? desktopServerUrl : '';
+        // Screens pick a session with ?session=today, current or a session ID; otherwise the meet's display filter applies
+        const session = new URLSearchParams(window.location.search).get('session');
+        const query = session ? `?session=${encodeURIComponent(session)}` : '';
+        const response = await fetch(`${baseUrl}/all-lif${query}`);
         if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
         const data = await response.json();
         setLifDataArray(data || []);
@@ -70,13 +83,34 @@ function Results() {
     return () => clearInterval(interval);
   }, [refreshFlag]);
 
+  // Fetch the club league table while a playlist is showing it
+  const [leagueTable, setLeagueTable] = useState([]);
+  useEffect(() => {
+    if (viewMode !== 'league') return undefined;
+    async function fetchLeagueTable() {
+      try {
+        const baseUrl = isDesktopApp ? desktopServerUrl : '';
+        const session = new URLSearchParams(window.location.search).get('session');
+        const query = session ? `?session=${encodeURIComponent(session)}` : '';
+        const response = await fetch(`${baseUrl}/league-table${query}`);
+        if (!response.ok) return;
+        setLeagueTable((await response.json()) || []);
+      } catch (err) {
+        console.error('Error fetching league table:', err);
+      }
+    }
+    fetchLeagueTable();
+    const interval = setInterval(fetchLeagueTable, 3000);
+    return () => clearInterval(interval);
+  }, [viewMode]);
+
   // Fetch custom club acronyms
   useEffect(() => {
     async function fetchAcronyms() {
       try {
         const hostname = window.location.hostname;
         const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-        const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
+        const baseUrl = isDesktop ? desktopServerUrl : '';
         const response = await fetch(`${baseUrl}/club-acronyms`);
         if (!response.ok) return;
         const data = await response.json();
@@ -98,8 +132,11 @@ function Results() {
       try {
         const hostname = window.location.hostname;
         const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
-        const baseUrl = isDesktop ? 'http://127.0.0.1:3000' : '';
-        const response = await fetch(`${baseUrl}/display-state`);
+        const baseUrl = isDesktop ? desktopServerUrl : '';
+        // Screens pick their display channel with ?channel=<name> in the URL
+        const channel = new URLSearchParams(window.location.search).get('channel');
+        const query = channel ? `?channel=${encodeURIComponent(channel)}` : '';
+        const response = await fetch(`${baseUrl}/display-state${query}`);
         if (!response.ok) return;
         const state = await response.json();
 
@@ -123,6 +160,11 @@ function Results() {
           setShowBib(state.showBib);
         }
 
+        // Playlists switch between the multi-result grid, the full screen result and the league table
+        if (state.view === 'multi' || state.view === 'fullscreen' || state.view === 'league') {
+          setViewMode(state.view);
+        }
+
         // Update display mode and overlays (text/screensaver)
         if (state.mode) {
           setSyncedDisplayMode(state.mode);
@@ -130,9 +172,12 @@ function Results() {
         if (state.activeText !== undefined) {
           setSyncedActiveText(state.activeText);
         }
-        if (state.imageBase64 !== undefined) {
-          setSyncedImageBase64(state.imageBase64);
-        }
+        // Screensaver images come from the media library, either a slideshow or a single image
+        const imageIds = state.slideshowIds && state.slideshowIds.length > 0
+          ? state.slideshowIds
+          : (state.imageId ? [state.imageId] : []);
+        setSyncedImageIds(prev => (prev.join(',') === imageIds.join(',') ? prev : imageIds));
+        setSlideshowInterval(state.slideshowInterval || 10);
       } catch (err) {
         console.error('Error fetching display state:', err);
       }
@@ -142,6 +187,13 @@ function Results() {
     return () => clearInterval(interval);
   }, []);
 
+  // Rotate through slideshow images
+  useEffect(() => {
+    if (syncedImageIds.length < 2) return undefined;
+    const interval = setInterval(() => setSlideIndex(prev => prev + 1), slideshowInterval * 1000);
+    return () => clearInterval(interval);
+  }, [syncedImageIds, slideshowInterval]);
+
   // Text size adjustment functions
   const incrementTextMultiplier = () => setTextMultiplier(prev => Math.min(prev + 5, 200));
   const decrementTextMultiplier = () => setTextMultiplier(prev => Math.max(prev - 5, 5));
@@ -597,6 +649,9 @@ function Results() {
         {/* Header row */}
         <div style={{ ...cellStyle, backgroundColor: theme.headerBg, color: theme.headerText, fontWeight: 'bold', gridColumn: `1 / ${headerSpan + 1}` }}>
           {data.eventName}
+          {data.amended && (
+            <span style={{ marginLeft: '0.5em', padding: '0 0.3em', fontSize: '0.7em', backgroundColor: '#c62828', color: '#fff', borderRadius: '3px' }}>AMENDED</span>
+          )}
         </div>
         <div style={{ ...cellStyle, backgroundColor: theme.headerBg, color: theme.headerText, fontWeight: 'bold', justifyContent: 'flex-end' }}>
           {data.wind}
@@ -652,18 +707,21 @@ function Results() {
     }
 
     // Show screensaver if active (matches App.jsx)
-    if (syncedDisplayMode === 'screensaver' && syncedImageBase64) {
+    if (syncedDisplayMode === 'screensaver' && syncedImageIds.length > 0) {
+      const baseUrl = isDesktopApp ? desktopServerUrl : '';
+      const mediaId = syncedImageIds[slideIndex % syncedImageIds.length];
+      const mediaStyle = {
+        width: '100%',
+        height: '100%',
+        objectFit: 'contain'
+      };
       return (
         <div style={containerStyle}>
-          <img
-            src={syncedImageBase64}
-            alt="Screensaver"
-            style={{
-              width: '100%',
-              height: '100%',
-              objectFit: 'contain'
-            }}
-          />
+          {/\.(mp4|webm)$/.test(mediaId) ? (
+            <video key={mediaId} src={`${baseUrl}/media/${mediaId}`} style={mediaStyle} autoPlay muted loop playsInline />
+          ) : (
+            <img src={`${baseUrl}/media/${mediaId}`} alt="Screensaver" style={mediaStyle} />
+          )}
         </div>
       );
     }
@@ -722,6 +780,9 @@ function Results() {
         {/* Header row */}
         <div style={{ ...cellStyle, backgroundColor: theme.headerBg, color: theme.headerText, fontWeight: 'bold', gridColumn: `1 / ${headerSpan + 1}` }}>
           {currentLIF.eventName}
+          {currentLIF.amended && (
+            <span style={{ marginLeft: '0.5em', padding: '0 0.3em', fontSize: '0.7em', backgroundColor: '#c62828', color: '#fff', borderRadius: '3px' }}>AMENDED</span>
+          )}
         </div>
         <div style={{ ...cellStyle, backgroundColor: theme.headerBg, color: theme.headerText, fontWeight: 'bold', justifyContent: 'flex-end' }}>
           {currentLIF.wind}
@@ -777,6 +838,28 @@ function Results() {
       {/* Full screen table view */}
       {viewMode === 'fullscreen' && <FullScreenTable />}
 
+      {/* League table view */}
+      {viewMode === 'league' && (
+        <div>
+          <h2 style={{ textAlign: 'center' }}>League Table</h2>
+          <table className="table table-dark table-striped" style={{ fontSize: `${textMultiplier / 20}rem` }}>
+            <thead>
+              <tr><th>Pos</th><th>Club</th><th className="text-end">Wins</th><th className="text-end">Points</th></tr>
+            </thead>
+            <tbody>
+              {leagueTable.map(row => (
+                <tr key={row.club}>
+                  <td>{row.position}</td>
+                  <td>{row.club}</td>
+                  <td className="text-end">{row.wins}</td>
+                  <td className="text-end" style={{ fontWeight: 'bold' }}>{row.points}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      )}
+
       {/* Fixed control panel positioned just above the bottom */}
       {/* Desktop: always show if not full screen. Web: show only if showControls is true */}
       {!isFullScreen && (isDesktopApp || showControls) && (
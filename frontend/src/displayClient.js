@@ -0,0 +1,88 @@
+// Registers this web display with the server and applies remote control
+// commands (reload, identify, rename, channel) sent back with each heartbeat.
+
+import { desktopServerUrl } from './serverUrl';
+
+const HEARTBEAT_INTERVAL = 5000;
+const ID_KEY = 'polyfield-display-id';
+const NAME_KEY = 'polyfield-display-name';
+
+function getBaseUrl() {
+  const hostname = window.location.hostname;
+  const isDesktop = hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:';
+  return isDesktop ? desktopServerUrl : '';
+}
+
+// Flash the display name in the middle of the screen for a few seconds
+function identify(name) {
+  const overlay = document.createElement('div');
+  overlay.textContent = name;
+  Object.assign(overlay.style, {
+    position: 'fixed',
+    inset: '0',
+    display: 'flex',
+    alignItems: 'center',
+    justifyContent: 'center',
+    backgroundColor: 'rgba(0, 0, 0, 0.85)',
+    color: '#ffeb3b',
+    fontSize: '10vw',
+    fontWeight: 'bold',
+    zIndex: 10000,
+  });
+  document.body.appendChild(overlay);
+  setTimeout(() => overlay.remove(), 5000);
+}
+
+function applyCommand(command) {
+  switch (command.action) {
+    case 'reload':
+      window.location.reload();
+      break;
+    case 'identify':
+      identify(command.value || localStorage.getItem(NAME_KEY) || 'Display');
+      break;
+    case 'rename':
+      localStorage.setItem(NAME_KEY, command.value);
+      break;
+    case 'channel': {
+      const url = new URL(window.location.href);
+      if (command.value && command.value !== 'default') {
+        url.searchParams.set('channel', command.value);
+      } else {
+        url.searchParams.delete('channel');
+      }
+      window.location.replace(url.toString());
+      break;
+    }
+    default:
+      break;
+  }
+}
+
+// startDisplayHeartbeat starts sending heartbeats and returns a function that stops them.
+export function startDisplayHeartbeat() {
+  async function beat() {
+    try {
+      const response = await fetch(`${getBaseUrl()}/displays/heartbeat`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          id: localStorage.getItem(ID_KEY) || '',
+          name: localStorage.getItem(NAME_KEY) || '',
+          channel: new URLSearchParams(window.location.search).get('channel') || '',
+          resolution: `${window.innerWidth}x${window.innerHeight}`,
+          view: window.location.pathname,
+        }),
+      });
+      if (!response.ok) return;
+      const result = await response.json();
+      if (result.id) localStorage.setItem(ID_KEY, result.id);
+      (result.commands || []).forEach(applyCommand);
+    } catch (err) {
+      // Server unreachable - try again on the next beat
+    }
+  }
+  beat();
+  const interval = setInterval(beat, HEARTBEAT_INTERVAL);
+  return () => clearInterval(interval);
+}
@@ -0,0 +1,20 @@
+// URL the desktop app uses to reach its own web server. The server falls back to
+// another port if the configured one is taken, so the real URL comes from the backend.
+import { GetServerURL } from '../wailsjs/go/main/App';
+
+export let desktopServerUrl = 'http://127.0.0.1:3000';
+
+// refreshServerUrl fetches the current server URL, e.g. after the server settings change.
+export async function refreshServerUrl() {
+  try {
+    desktopServerUrl = await GetServerURL();
+  } catch (err) {
+    // Not running in the desktop app - web pages use relative URLs
+  }
+  return desktopServerUrl;
+}
+
+const hostname = window.location.hostname;
+if (hostname === '' || hostname === 'wails.localhost' || window.location.protocol === 'wails:') {
+  refreshServerUrl();
+}
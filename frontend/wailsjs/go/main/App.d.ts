@@ -2,26 +2,166 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function ApproveResult(arg1:string):Promise<void>;
+
+export function ArchiveMeet():Promise<string>;
+
+export function AssignDisplayChannel(arg1:string,arg2:string):Promise<void>;
+
+export function AssignPlaylist(arg1:string,arg2:string):Promise<void>;
+
+export function CheckRankings(arg1:string,arg2:string):Promise<main.RankingReport>;
+
 export function ChooseDirectory():Promise<string>;
 
+export function CloseArchive():Promise<void>;
+
+export function CreateAccessKey(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function CreateChannel(arg1:string):Promise<void>;
+
+export function DeleteAccessKey(arg1:string):Promise<void>;
+
+export function DeleteChannel(arg1:string):Promise<void>;
+
+export function DeleteMedia(arg1:string):Promise<void>;
+
+export function DeletePlaylist(arg1:string):Promise<void>;
+
+export function DiscoverInstances():Promise<Array<main.DiscoveredInstance>>;
+
 export function EnterFullScreen():Promise<void>;
 
 export function ExitFullScreen():Promise<void>;
 
+export function ExportAuditLog(arg1:string):Promise<string>;
+
+export function ExportLogs():Promise<string>;
+
+export function ExportRankings(arg1:string,arg2:string):Promise<main.RankingReport>;
+
+export function ExportResults(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ExportSite():Promise<string>;
+
+export function GetAccessKeys():Promise<Array<main.AccessKey>>;
+
 export function GetAllLIFData():Promise<Array<main.LifData>>;
 
+export function GetAmendments(arg1:string):Promise<Array<main.ResultDiff>>;
+
+export function GetArchiveInfo():Promise<main.ArchiveManifest>;
+
+export function GetAuditLog(arg1:main.AuditQuery):Promise<Array<main.AuditEntry>>;
+
+export function GetCACertificatePath():Promise<string>;
+
+export function GetChannelDisplayState(arg1:string):Promise<main.DisplayState>;
+
+export function GetChannels():Promise<Array<string>>;
+
+export function GetDebugLogs():Promise<Array<string>>;
+
+export function GetDiagnostics():Promise<Array<main.FileDiagnostics>>;
+
 export function GetDisplayState():Promise<main.DisplayState>;
 
+export function GetDisplays():Promise<Array<main.ConnectedDisplay>>;
+
+export function GetHTTPSEnabled():Promise<boolean>;
+
+export function GetHealth():Promise<main.Health>;
+
+export function GetHoldForApproval():Promise<boolean>;
+
+export function GetLogLevel():Promise<string>;
+
+export function GetMDNSSettings():Promise<main.MDNSSettings>;
+
+export function GetMedia():Promise<Array<main.MediaItem>>;
+
+export function GetMeet():Promise<main.Meet>;
+
+export function GetMeetSettingsEnabled():Promise<boolean>;
+
+export function GetMonitoredDirectory():Promise<string>;
+
+export function GetOperatorToken():Promise<string>;
+
+export function GetOverrides():Promise<Array<main.ResultOverride>>;
+
+export function GetPendingResults():Promise<Array<main.PendingResult>>;
+
+export function GetPlaylists():Promise<Array<main.Playlist>>;
+
+export function GetReplicationSettings():Promise<main.ReplicationSettings>;
+
+export function GetReplicationStatus():Promise<main.ReplicationStatus>;
+
+export function GetServerSettings():Promise<main.ServerSettings>;
+
+export function GetServerURL():Promise<string>;
+
+export function GetSessionResults(arg1:string):Promise<Array<main.LifData>>;
+
+export function GetSessions():Promise<Array<main.Session>>;
+
 export function GetWebInterfaceInfo():Promise<string>;
 
+export function IdentifyDisplay(arg1:string):Promise<void>;
+
+export function OpenArchive():Promise<main.ArchiveManifest>;
+
+export function RejectResult(arg1:string):Promise<void>;
+
+export function ReloadDisplay(arg1:string):Promise<void>;
+
+export function RemoveOverride(arg1:string,arg2:string):Promise<void>;
+
+export function RenameDisplay(arg1:string,arg2:string):Promise<void>;
+
 export function SaveGraphic(arg1:string,arg2:string):Promise<string>;
 
+export function SavePlaylist(arg1:main.Playlist):Promise<void>;
+
+export function SaveResultSheet(arg1:string):Promise<string>;
+
+export function SaveSessionResultSheets(arg1:string):Promise<string>;
+
+export function SetChannelDisplayState(arg1:string,arg2:main.DisplayState):Promise<void>;
+
+export function SetChannelSlideshow(arg1:string,arg2:Array<string>,arg3:number):Promise<void>;
+
 export function SetCurrentLIF(arg1:main.LifData):Promise<void>;
 
 export function SetDisplayState(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function SetHTTPSEnabled(arg1:boolean):Promise<void>;
+
+export function SetHoldForApproval(arg1:boolean):Promise<void>;
+
 export function SetLayoutTheme(arg1:string):Promise<void>;
 
+export function SetLogLevel(arg1:string):Promise<void>;
+
+export function SetMDNSSettings(arg1:string,arg2:string):Promise<void>;
+
+export function SetMeet(arg1:main.Meet):Promise<void>;
+
+export function SetMeetSettingsEnabled(arg1:boolean):Promise<void>;
+
+export function SetMonitoredDirectory(arg1:string):Promise<void>;
+
+export function SetOverride(arg1:main.ResultOverride):Promise<void>;
+
+export function SetReplicationSettings(arg1:main.ReplicationSettings):Promise<void>;
+
 export function SetRotationMode(arg1:string):Promise<void>;
 
+export function SetServerSettings(arg1:string,arg2:number):Promise<void>;
+
 export function SetShowBib(arg1:boolean):Promise<void>;
+
+export function UpdatePendingResult(arg1:string,arg2:main.LifData):Promise<void>;
+
+export function UploadMedia(arg1:string,arg2:string):Promise<main.MediaItem>;
@@ -2,10 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApproveResult(arg1) {
+  return window['go']['main']['App']['ApproveResult'](arg1);
+}
+
+export function ArchiveMeet() {
+  return window['go']['main']['App']['ArchiveMeet']();
+}
+
+export function AssignDisplayChannel(arg1, arg2) {
+  return window['go']['main']['App']['AssignDisplayChannel'](arg1, arg2);
+}
+
+export function AssignPlaylist(arg1, arg2) {
+  return window['go']['main']['App']['AssignPlaylist'](arg1, arg2);
+}
+
+export function CheckRankings(arg1, arg2) {
+  return window['go']['main']['App']['CheckRankings'](arg1, arg2);
+}
+
 export function ChooseDirectory() {
   return window['go']['main']['App']['ChooseDirectory']();
 }
 
+export function CloseArchive() {
+  return window['go']['main']['App']['CloseArchive']();
+}
+
+export function CreateAccessKey(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateAccessKey'](arg1, arg2, arg3);
+}
+
+export function CreateChannel(arg1) {
+  return window['go']['main']['App']['CreateChannel'](arg1);
+}
+
+export function DeleteAccessKey(arg1) {
+  return window['go']['main']['App']['DeleteAccessKey'](arg1);
+}
+
+export function DeleteChannel(arg1) {
+  return window['go']['main']['App']['DeleteChannel'](arg1);
+}
+
+export function DeleteMedia(arg1) {
+  return window['go']['main']['App']['DeleteMedia'](arg1);
+}
+
+export function DeletePlaylist(arg1) {
+  return window['go']['main']['App']['DeletePlaylist'](arg1);
+}
+
+export function DiscoverInstances() {
+  return window['go']['main']['App']['DiscoverInstances']();
+}
+
 export function EnterFullScreen() {
   return window['go']['main']['App']['EnterFullScreen']();
 }
@@ -14,22 +66,202 @@ export function ExitFullScreen() {
   return window['go']['main']['App']['ExitFullScreen']();
 }
 
+export function ExportAuditLog(arg1) {
+  return window['go']['main']['App']['ExportAuditLog'](arg1);
+}
+
+export function ExportLogs() {
+  return window['go']['main']['App']['ExportLogs']();
+}
+
+export function ExportRankings(arg1, arg2) {
+  return window['go']['main']['App']['ExportRankings'](arg1, arg2);
+}
+
+export function ExportResults(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportResults'](arg1, arg2, arg3);
+}
+
+export function ExportSite() {
+  return window['go']['main']['App']['ExportSite']();
+}
+
+export function GetAccessKeys() {
+  return window['go']['main']['App']['GetAccessKeys']();
+}
+
 export function GetAllLIFData() {
   return window['go']['main']['App']['GetAllLIFData']();
 }
 
+export function GetAmendments(arg1) {
+  return window['go']['main']['App']['GetAmendments'](arg1);
+}
+
+export function GetArchiveInfo() {
+  return window['go']['main']['App']['GetArchiveInfo']();
+}
+
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
+export function GetCACertificatePath() {
+  return window['go']['main']['App']['GetCACertificatePath']();
+}
+
+export function GetChannelDisplayState(arg1) {
+  return window['go']['main']['App']['GetChannelDisplayState'](arg1);
+}
+
+export function GetChannels() {
+  return window['go']['main']['App']['GetChannels']();
+}
+
+export function GetDebugLogs() {
+  return window['go']['main']['App']['GetDebugLogs']();
+}
+
+export function GetDiagnostics() {
+  return window['go']['main']['App']['GetDiagnostics']();
+}
+
 export function GetDisplayState() {
   return window['go']['main']['App']['GetDisplayState']();
 }
 
+export function GetDisplays() {
+  return window['go']['main']['App']['GetDisplays']();
+}
+
+export function GetHTTPSEnabled() {
+  return window['go']['main']['App']['GetHTTPSEnabled']();
+}
+
+export function GetHealth() {
+  return window['go']['main']['App']['GetHealth']();
+}
+
+export function GetHoldForApproval() {
+  return window['go']['main']['App']['GetHoldForApproval']();
+}
+
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
+export function GetMDNSSettings() {
+  return window['go']['main']['App']['GetMDNSSettings']();
+}
+
+export function GetMedia() {
+  return window['go']['main']['App']['GetMedia']();
+}
+
+export function GetMeet() {
+  return window['go']['main']['App']['GetMeet']();
+}
+
+export function GetMeetSettingsEnabled() {
+  return window['go']['main']['App']['GetMeetSettingsEnabled']();
+}
+
+export function GetMonitoredDirectory() {
+  return window['go']['main']['App']['GetMonitoredDirectory']();
+}
+
+export function GetOperatorToken() {
+  return window['go']['main']['App']['GetOperatorToken']();
+}
+
+export function GetOverrides() {
+  return window['go']['main']['App']['GetOverrides']();
+}
+
+export function GetPendingResults() {
+  return window['go']['main']['App']['GetPendingResults']();
+}
+
+export function GetPlaylists() {
+  return window['go']['main']['App']['GetPlaylists']();
+}
+
+export function GetReplicationSettings() {
+  return window['go']['main']['App']['GetReplicationSettings']();
+}
+
+export function GetReplicationStatus() {
+  return window['go']['main']['App']['GetReplicationStatus']();
+}
+
+export function GetServerSettings() {
+  return window['go']['main']['App']['GetServerSettings']();
+}
+
+export function GetServerURL() {
+  return window['go']['main']['App']['GetServerURL']();
+}
+
+export function GetSessionResults(arg1) {
+  return window['go']['main']['App']['GetSessionResults'](arg1);
+}
+
+export function GetSessions() {
+  return window['go']['main']['App']['GetSessions']();
+}
+
 export function GetWebInterfaceInfo() {
   return window['go']['main']['App']['GetWebInterfaceInfo']();
 }
 
+export function IdentifyDisplay(arg1) {
+  return window['go']['main']['App']['IdentifyDisplay'](arg1);
+}
+
+export function OpenArchive() {
+  return window['go']['main']['App']['OpenArchive']();
+}
+
+export function RejectResult(arg1) {
+  return window['go']['main']['App']['RejectResult'](arg1);
+}
+
+export function ReloadDisplay(arg1) {
+  return window['go']['main']['App']['ReloadDisplay'](arg1);
+}
+
+export function RemoveOverride(arg1, arg2) {
+  return window['go']['main']['App']['RemoveOverride'](arg1, arg2);
+}
+
+export function RenameDisplay(arg1, arg2) {
+  return window['go']['main']['App']['RenameDisplay'](arg1, arg2);
+}
+
 export function SaveGraphic(arg1, arg2) {
   return window['go']['main']['App']['SaveGraphic'](arg1, arg2);
 }
 
+export function SavePlaylist(arg1) {
+  return window['go']['main']['App']['SavePlaylist'](arg1);
+}
+
+export function SaveResultSheet(arg1) {
+  return window['go']['main']['App']['SaveResultSheet'](arg1);
+}
+
+export function SaveSessionResultSheets(arg1) {
+  return window['go']['main']['App']['SaveSessionResultSheets'](arg1);
+}
+
+export function SetChannelDisplayState(arg1, arg2) {
+  return window['go']['main']['App']['SetChannelDisplayState'](arg1, arg2);
+}
+
+export function SetChannelSlideshow(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetChannelSlideshow'](arg1, arg2, arg3);
+}
+
 export function SetCurrentLIF(arg1) {
   return window['go']['main']['App']['SetCurrentLIF'](arg1);
 }
@@ -38,14 +270,62 @@ export function SetDisplayState(arg1, arg2, arg3) {
   return window['go']['main']['App']['SetDisplayState'](arg1, arg2, arg3);
 }
 
+export function SetHTTPSEnabled(arg1) {
+  return window['go']['main']['App']['SetHTTPSEnabled'](arg1);
+}
+
+export function SetHoldForApproval(arg1) {
+  return window['go']['main']['App']['SetHoldForApproval'](arg1);
+}
+
 export function SetLayoutTheme(arg1) {
   return window['go']['main']['App']['SetLayoutTheme'](arg1);
 }
 
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetMDNSSettings(arg1, arg2) {
+  return window['go']['main']['App']['SetMDNSSettings'](arg1, arg2);
+}
+
+export function SetMeet(arg1) {
+  return window['go']['main']['App']['SetMeet'](arg1);
+}
+
+export function SetMeetSettingsEnabled(arg1) {
+  return window['go']['main']['App']['SetMeetSettingsEnabled'](arg1);
+}
+
+export function SetMonitoredDirectory(arg1) {
+  return window['go']['main']['App']['SetMonitoredDirectory'](arg1);
+}
+
+export function SetOverride(arg1) {
+  return window['go']['main']['App']['SetOverride'](arg1);
+}
+
+export function SetReplicationSettings(arg1) {
+  return window['go']['main']['App']['SetReplicationSettings'](arg1);
+}
+
 export function SetRotationMode(arg1) {
   return window['go']['main']['App']['SetRotationMode'](arg1);
 }
 
+export function SetServerSettings(arg1, arg2) {
+  return window['go']['main']['App']['SetServerSettings'](arg1, arg2);
+}
+
 export function SetShowBib(arg1) {
   return window['go']['main']['App']['SetShowBib'](arg1);
 }
+
+export function UpdatePendingResult(arg1, arg2) {
+  return window['go']['main']['App']['UpdatePendingResult'](arg1, arg2);
+}
+
+export function UploadMedia(arg1, arg2) {
+  return window['go']['main']['App']['UploadMedia'](arg1, arg2);
+}
@@ -1,5 +1,199 @@
 export namespace main {
 	
+	export class AccessKey {
+	    id: string;
+	    name: string;
+	    role: string;
+	    created: number;
+	    lastUsed: number;
+	    hash?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AccessKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.role = source["role"];
+	        this.created = source["created"];
+	        this.lastUsed = source["lastUsed"];
+	        this.hash = source["hash"];
+	    }
+	}
+	export class MediaItem {
+	    id: string;
+	    name: string;
+	    contentType: string;
+	    size: number;
+	    uploaded: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MediaItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.contentType = source["contentType"];
+	        this.size = source["size"];
+	        this.uploaded = source["uploaded"];
+	    }
+	}
+	export class MeetSession {
+	    name: string;
+	    date: string;
+	    start: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.date = source["date"];
+	        this.start = source["start"];
+	    }
+	}
+	export class Meet {
+	    name: string;
+	    venue: string;
+	    startDate: string;
+	    endDate: string;
+	    sessions: MeetSession[];
+	    sessionBreaks: string[];
+	    displayFilter: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Meet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.venue = source["venue"];
+	        this.startDate = source["startDate"];
+	        this.endDate = source["endDate"];
+	        this.sessions = this.convertValues(source["sessions"], MeetSession);
+	        this.sessionBreaks = source["sessionBreaks"];
+	        this.displayFilter = source["displayFilter"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ArchiveManifest {
+	    format: string;
+	    version: number;
+	    appVersion: string;
+	    created: number;
+	    sourceDir: string;
+	    meet: Meet;
+	    results: number;
+	    media: MediaItem[];
+	    path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.version = source["version"];
+	        this.appVersion = source["appVersion"];
+	        this.created = source["created"];
+	        this.sourceDir = source["sourceDir"];
+	        this.meet = this.convertValues(source["meet"], Meet);
+	        this.results = source["results"];
+	        this.media = this.convertValues(source["media"], MediaItem);
+	        this.path = source["path"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditEntry {
+	    time: number;
+	    action: string;
+	    source: string;
+	    channel?: string;
+	    file?: string;
+	    hash?: string;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.action = source["action"];
+	        this.source = source["source"];
+	        this.channel = source["channel"];
+	        this.file = source["file"];
+	        this.hash = source["hash"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class AuditQuery {
+	    action: string;
+	    source: string;
+	    channel: string;
+	    file: string;
+	    since: number;
+	    until: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.source = source["source"];
+	        this.channel = source["channel"];
+	        this.file = source["file"];
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.limit = source["limit"];
+	    }
+	}
 	export class Competitor {
 	    place: string;
 	    id: string;
@@ -22,12 +216,113 @@ export namespace main {
 	        this.time = source["time"];
 	    }
 	}
+	export class CompetitorChange {
+	    id: string;
+	    firstName: string;
+	    lastName: string;
+	    field: string;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompetitorChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.firstName = source["firstName"];
+	        this.lastName = source["lastName"];
+	        this.field = source["field"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class ConnectedDisplay {
+	    id: string;
+	    name: string;
+	    channel: string;
+	    resolution: string;
+	    view: string;
+	    address: string;
+	    lastHeartbeat: number;
+	    online: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectedDisplay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.channel = source["channel"];
+	        this.resolution = source["resolution"];
+	        this.view = source["view"];
+	        this.address = source["address"];
+	        this.lastHeartbeat = source["lastHeartbeat"];
+	        this.online = source["online"];
+	    }
+	}
+	export class DiscoveredInstance {
+	    name: string;
+	    hostname: string;
+	    address: string;
+	    port: number;
+	    url: string;
+	    version: string;
+	    meet: string;
+	    apiPath: string;
+	    hostnameConflict: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscoveredInstance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.hostname = source["hostname"];
+	        this.address = source["address"];
+	        this.port = source["port"];
+	        this.url = source["url"];
+	        this.version = source["version"];
+	        this.meet = source["meet"];
+	        this.apiPath = source["apiPath"];
+	        this.hostnameConflict = source["hostnameConflict"];
+	    }
+	}
+	export class RowIssue {
+	    row: number;
+	    kind: string;
+	    reason: string;
+	    skipped: boolean;
+	    raw: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RowIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.kind = source["kind"];
+	        this.reason = source["reason"];
+	        this.skipped = source["skipped"];
+	        this.raw = source["raw"];
+	    }
+	}
 	export class LifData {
 	    fileName: string;
 	    eventName: string;
+	    round?: string;
+	    heat?: string;
 	    wind: string;
 	    competitors: Competitor[];
+	    dns?: Competitor[];
 	    modifiedTime: number;
+	    amended: boolean;
+	    warnings?: RowIssue[];
 	
 	    static createFrom(source: any = {}) {
 	        return new LifData(source);
@@ -37,9 +332,14 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.fileName = source["fileName"];
 	        this.eventName = source["eventName"];
+	        this.round = source["round"];
+	        this.heat = source["heat"];
 	        this.wind = source["wind"];
 	        this.competitors = this.convertValues(source["competitors"], Competitor);
+	        this.dns = this.convertValues(source["dns"], Competitor);
 	        this.modifiedTime = source["modifiedTime"];
+	        this.amended = source["amended"];
+	        this.warnings = this.convertValues(source["warnings"], RowIssue);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -68,6 +368,11 @@ export namespace main {
 	    layoutTheme: string;
 	    currentLIF?: LifData;
 	    showBib: boolean;
+	    imageId: string;
+	    slideshowIds: string[];
+	    slideshowInterval: number;
+	    view: string;
+	    playlist: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DisplayState(source);
@@ -82,6 +387,49 @@ export namespace main {
 	        this.layoutTheme = source["layoutTheme"];
 	        this.currentLIF = this.convertValues(source["currentLIF"], LifData);
 	        this.showBib = source["showBib"];
+	        this.imageId = source["imageId"];
+	        this.slideshowIds = source["slideshowIds"];
+	        this.slideshowInterval = source["slideshowInterval"];
+	        this.view = source["view"];
+	        this.playlist = source["playlist"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileDiagnostics {
+	    fileName: string;
+	    charset: string;
+	    error: string;
+	    warnings: RowIssue[];
+	    modifiedTime: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileName = source["fileName"];
+	        this.charset = source["charset"];
+	        this.error = source["error"];
+	        this.warnings = this.convertValues(source["warnings"], RowIssue);
+	        this.modifiedTime = source["modifiedTime"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -102,6 +450,345 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class Health {
+	    status: string;
+	    problems: string[];
+	    uptimeSeconds: number;
+	    directory: string;
+	    watching: boolean;
+	    filesWatched: number;
+	    secondsSinceLastResult: number;
+	    replicationRole: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Health(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.problems = source["problems"];
+	        this.uptimeSeconds = source["uptimeSeconds"];
+	        this.directory = source["directory"];
+	        this.watching = source["watching"];
+	        this.filesWatched = source["filesWatched"];
+	        this.secondsSinceLastResult = source["secondsSinceLastResult"];
+	        this.replicationRole = source["replicationRole"];
+	    }
+	}
+	
+	export class MDNSSettings {
+	    instanceName: string;
+	    hostname: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MDNSSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.instanceName = source["instanceName"];
+	        this.hostname = source["hostname"];
+	    }
+	}
+	
+	
+	
+	export class ResultDiff {
+	    fileName: string;
+	    eventName: string;
+	    time: number;
+	    added: Competitor[];
+	    removed: Competitor[];
+	    changes: CompetitorChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileName = source["fileName"];
+	        this.eventName = source["eventName"];
+	        this.time = source["time"];
+	        this.added = this.convertValues(source["added"], Competitor);
+	        this.removed = this.convertValues(source["removed"], Competitor);
+	        this.changes = this.convertValues(source["changes"], CompetitorChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PendingResult {
+	    id: string;
+	    data?: LifData;
+	    receivedAt: number;
+	    diff?: ResultDiff;
+	    hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.data = this.convertValues(source["data"], LifData);
+	        this.receivedAt = source["receivedAt"];
+	        this.diff = this.convertValues(source["diff"], ResultDiff);
+	        this.hash = source["hash"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlaylistItem {
+	    type: string;
+	    mediaId: string;
+	    text: string;
+	    duration: number;
+	    start: string;
+	    end: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlaylistItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.mediaId = source["mediaId"];
+	        this.text = source["text"];
+	        this.duration = source["duration"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class Playlist {
+	    name: string;
+	    items: PlaylistItem[];
+	    resultHold: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Playlist(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.items = this.convertValues(source["items"], PlaylistItem);
+	        this.resultHold = source["resultHold"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RankingIssue {
+	    file: string;
+	    event: string;
+	    row: number;
+	    athlete: string;
+	    problem: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankingIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.event = source["event"];
+	        this.row = source["row"];
+	        this.athlete = source["athlete"];
+	        this.problem = source["problem"];
+	    }
+	}
+	export class RankingReport {
+	    format: string;
+	    rows: number;
+	    issues: RankingIssue[];
+	    path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankingReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.rows = source["rows"];
+	        this.issues = this.convertValues(source["issues"], RankingIssue);
+	        this.path = source["path"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplicationSettings {
+	    role: string;
+	    primaryUrl: string;
+	    key: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplicationSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.primaryUrl = source["primaryUrl"];
+	        this.key = source["key"];
+	    }
+	}
+	export class ReplicationStatus {
+	    role: string;
+	    active: boolean;
+	    lastSync: number;
+	    lastError: string;
+	    backupAddress: string;
+	    backupLastSeen: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplicationStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.active = source["active"];
+	        this.lastSync = source["lastSync"];
+	        this.lastError = source["lastError"];
+	        this.backupAddress = source["backupAddress"];
+	        this.backupLastSeen = source["backupLastSeen"];
+	    }
+	}
+	
+	export class ResultOverride {
+	    fileName: string;
+	    bib: string;
+	    firstName: string;
+	    lastName: string;
+	    affiliation: string;
+	    status: string;
+	    hidden: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultOverride(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileName = source["fileName"];
+	        this.bib = source["bib"];
+	        this.firstName = source["firstName"];
+	        this.lastName = source["lastName"];
+	        this.affiliation = source["affiliation"];
+	        this.status = source["status"];
+	        this.hidden = source["hidden"];
+	    }
+	}
+	
+	export class ServerSettings {
+	    listenAddress: string;
+	    port: number;
+	    activePort: number;
+	    activeHttpsPort: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServerSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.listenAddress = source["listenAddress"];
+	        this.port = source["port"];
+	        this.activePort = source["activePort"];
+	        this.activeHttpsPort = source["activeHttpsPort"];
+	    }
+	}
+	export class Session {
+	    id: string;
+	    name: string;
+	    start: number;
+	    end: number;
+	    results: number;
+	    current: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.results = source["results"];
+	        this.current = source["current"];
+	    }
+	}
 
 }
 
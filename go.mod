@@ -4,6 +4,7 @@ go 1.24.1
 
 require (
 	github.com/fsnotify/fsnotify v1.8.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/gofiber/fiber/v2 v2.52.6
 	github.com/hashicorp/mdns v1.0.6
 	github.com/saintfish/chardet v0.0.0-20230101081208-5e3ef4b5456d
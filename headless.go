@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// adminKeyEnv is the environment variable headless mode reads the admin key from,
+// so it doesn't have to appear in the process list.
+const adminKeyEnv = "POLYFIELD_ADMIN_KEY"
+
+// runHeadless runs the results server without the desktop window, e.g. on a Linux
+// mini PC or Raspberry Pi. Flags override the saved settings, and everything the
+// desktop app can do is available through the REST API. It returns once the
+// server has shut down after SIGINT or SIGTERM.
+func runHeadless(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	dir := flags.String("dir", "", "results directory to monitor")
+	listen := flags.String("listen", "", "address to listen on (default 0.0.0.0)")
+	port := flags.Int("port", 0, fmt.Sprintf("HTTP port (default %d)", defaultPort))
+	config := flags.String("config", "", "settings file to read and save instead of the app config")
+	https := flags.Bool("https", false, "also serve over HTTPS")
+	hold := flags.Bool("hold-for-approval", false, "hold new results until they are approved")
+	adminKey := flags.String("admin-key", "", "admin key for the REST API (default $"+adminKeyEnv+", or a generated key)")
+	level := flags.String("log-level", "", "log level: debug, info, warn or error (default info)")
+	archive := flags.String("archive", "", "meet archive to serve, read-only, instead of the results directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	openLogFile()
+	app := NewApp()
+	app.settingsFile = *config
+	app.loadMediaIndex()
+	app.loadAccessKeys()
+	app.loadSettings()
+
+	if *adminKey == "" {
+		*adminKey = os.Getenv(adminKeyEnv)
+	}
+	if *adminKey != "" {
+		app.mu.Lock()
+		app.operatorToken = *adminKey
+		app.mu.Unlock()
+	} else {
+		// Straight to stderr: log files can be downloaded from /logs.
+		fmt.Fprintf(os.Stderr, "Admin key for this session: %s\n", app.GetOperatorToken())
+	}
+
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["log-level"] {
+		if err := setLogLevel(*level); err != nil {
+			return err
+		}
+	}
+	if set["listen"] || set["port"] {
+		address, configured := app.serverAddress()
+		if set["listen"] {
+			address = *listen
+		}
+		if set["port"] {
+			configured = *port
+		}
+		address, err := normaliseServerAddress(address, configured)
+		if err != nil {
+			return err
+		}
+		app.mu.Lock()
+		app.listenAddress = address
+		app.port = configured
+		app.mu.Unlock()
+	}
+	if set["https"] {
+		app.mu.Lock()
+		app.httpsEnabled = *https
+		app.mu.Unlock()
+	}
+	if *dir != "" {
+		if err := app.SetMonitoredDirectory(*dir); err != nil {
+			return err
+		}
+	} else if app.GetMonitoredDirectory() == "" && *archive == "" {
+		appLog.Warn("No results directory set, choose one with -dir or POST /settings/directory")
+	}
+	if set["hold-for-approval"] {
+		app.SetHoldForApproval(*hold)
+	}
+	if *archive != "" {
+		if _, err := app.loadArchive(*archive); err != nil {
+			return err
+		}
+	}
+
+	if err := StartFiberServer(app); err != nil {
+		return err
+	}
+	appLog.Info("Headless server running", "url", app.localServerURL())
+	go app.runPlaylists()
+	go app.runReplication()
+	go app.watchMDNS()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	appLog.Info("Shutting down")
+	app.shutdown()
+	return nil
+}
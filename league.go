@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultLeaguePoints is what a win scores in the league table; second place scores
+// one less, and so on down to one point.
+const defaultLeaguePoints = 8
+
+// LeagueStanding is one club's line in the league table.
+type LeagueStanding struct {
+	Position int    `json:"position"`
+	Club     string `json:"club"`
+	Points   int    `json:"points"`
+	Wins     int    `json:"wins"`
+	Scorers  int    `json:"scorers"` // Placed results that scored points
+}
+
+// leagueTable scores every placed competitor in results for their club: first
+// place earns firstPlace points, each place after that one fewer, and every other
+// placed finisher one point. DQ, DNF and unattached competitors don't score. Clubs
+// on equal points share a position.
+func leagueTable(results []*LifData, firstPlace int) []LeagueStanding {
+	if firstPlace <= 0 {
+		firstPlace = defaultLeaguePoints
+	}
+	clubs := make(map[string]*LeagueStanding)
+	for _, data := range results {
+		for _, c := range data.Competitors {
+			club := strings.TrimSpace(c.Affiliation)
+			place, err := strconv.Atoi(strings.TrimSpace(c.Place))
+			if club == "" || err != nil || place < 1 {
+				continue
+			}
+			standing, ok := clubs[strings.ToLower(club)]
+			if !ok {
+				standing = &LeagueStanding{Club: club}
+				clubs[strings.ToLower(club)] = standing
+			}
+			standing.Points += max(firstPlace-place+1, 1)
+			standing.Scorers++
+			if place == 1 {
+				standing.Wins++
+			}
+		}
+	}
+
+	table := make([]LeagueStanding, 0, len(clubs))
+	for _, standing := range clubs {
+		table = append(table, *standing)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Points != table[j].Points {
+			return table[i].Points > table[j].Points
+		}
+		if table[i].Wins != table[j].Wins {
+			return table[i].Wins > table[j].Wins
+		}
+		return table[i].Club < table[j].Club
+	})
+	for i := range table {
+		table[i].Position = i + 1
+		if i > 0 && table[i].Points == table[i-1].Points {
+			table[i].Position = table[i-1].Position
+		}
+	}
+	return table
+}
+
+// registerLeagueRoutes adds the league table endpoint, shown by 'leagueTable'
+// playlist items, to the Fiber server. Like /all-lif it needs no key; ?session=
+// picks the session and ?points= what a win scores.
+func registerLeagueRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/league-table", func(c *fiber.Ctx) error {
+		results, err := app.GetAllLIFData()
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		meet := app.GetMeet()
+		if results, err = meet.filterResults(results, c.Query("session", meet.DisplayFilter), time.Now()); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(leagueTable(results, c.QueryInt("points", defaultLeaguePoints)))
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeagueTable(t *testing.T) {
+	race := func(places ...string) *LifData {
+		data := &LifData{}
+		for i := 0; i+1 < len(places); i += 2 {
+			data.Competitors = append(data.Competitors, Competitor{Place: places[i], Affiliation: places[i+1]})
+		}
+		return data
+	}
+
+	tests := []struct {
+		name       string
+		results    []*LifData
+		firstPlace int
+		want       []LeagueStanding
+	}{
+		{
+			name:    "no results",
+			results: nil,
+			want:    []LeagueStanding{},
+		},
+		{
+			name:    "one race",
+			results: []*LifData{race("1", "Kingston", "2", "Sutton", "3", "Kingston")},
+			want: []LeagueStanding{
+				{Position: 1, Club: "Kingston", Points: 14, Wins: 1, Scorers: 2},
+				{Position: 2, Club: "Sutton", Points: 7, Scorers: 1},
+			},
+		},
+		{
+			name:       "places beyond the points scale score one",
+			results:    []*LifData{race("1", "Kingston", "2", "Sutton", "3", "Herne Hill", "4", "Herne Hill")},
+			firstPlace: 2,
+			want: []LeagueStanding{
+				{Position: 1, Club: "Kingston", Points: 2, Wins: 1, Scorers: 1},
+				{Position: 1, Club: "Herne Hill", Points: 2, Scorers: 2},
+				{Position: 3, Club: "Sutton", Points: 1, Scorers: 1},
+			},
+		},
+		{
+			name: "unplaced and unattached competitors don't score",
+			results: []*LifData{
+				race("1", "", "2", "Sutton", "", "Kingston"),
+				race("1", "sutton", "DNF", "Kingston"),
+			},
+			want: []LeagueStanding{
+				{Position: 1, Club: "Sutton", Points: 15, Wins: 1, Scorers: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leagueTable(tt.results, tt.firstPlace)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("leagueTable() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
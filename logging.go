@@ -0,0 +1,304 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// logFileName is the current log file in the log directory. Older files get a
+	// numbered suffix, polyfield.log.1 being the most recent.
+	logFileName = "polyfield.log"
+	// maxLogSize is the size a log file grows to before it is rotated.
+	maxLogSize = 5 * 1024 * 1024
+	// logFilesKept is how many rotated log files are kept besides the current one.
+	logFilesKept = 4
+	// recentLogLines is how many lines are kept in memory for the desktop debug log.
+	recentLogLines = 200
+)
+
+// logLevel is the minimum level written, changeable while running.
+var logLevel = new(slog.LevelVar)
+
+// logSink receives every formatted log line and writes it to the console, the
+// log file and the in-memory list of recent lines.
+var logSink = &logWriter{console: os.Stderr}
+
+// rootLogger writes 'key=value' lines. Each part of the app logs through its own
+// component logger below; the standard log package is redirected to it in init,
+// so any remaining log.Printf calls are logged at info level.
+var rootLogger = slog.New(slog.NewTextHandler(logSink, &slog.HandlerOptions{Level: logLevel}))
+
+// Component loggers.
+var (
+	appLog         = rootLogger.With("component", "app")
+	parserLog      = rootLogger.With("component", "parser")
+	watcherLog     = rootLogger.With("component", "watcher")
+	resultsLog     = rootLogger.With("component", "results") // approval, amendments and corrections
+	displayLog     = rootLogger.With("component", "display") // display state, channels, displays and playlists
+	mediaLog       = rootLogger.With("component", "media")
+	settingsLog    = rootLogger.With("component", "settings")
+	serverLog      = rootLogger.With("component", "server")
+	httpsLog       = rootLogger.With("component", "https")
+	authLog        = rootLogger.With("component", "auth")
+	mdnsLog        = rootLogger.With("component", "mdns")
+	replicationLog = rootLogger.With("component", "replication")
+)
+
+func init() {
+	slog.SetDefault(rootLogger)
+}
+
+// logWriter is an io.Writer that fans log lines out and rotates the log file.
+type logWriter struct {
+	mu      sync.Mutex
+	console io.Writer
+	dir     string // empty until openLogFile is called
+	file    *os.File
+	size    int64
+	recent  []string
+}
+
+// Write writes one log line. The slog handler calls it once per record.
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.console != nil {
+		w.console.Write(p)
+	}
+	w.recent = append(w.recent, strings.TrimRight(string(p), "\n"))
+	if len(w.recent) > recentLogLines {
+		w.recent = w.recent[len(w.recent)-recentLogLines:]
+	}
+	if w.file == nil {
+		return len(p), nil
+	}
+	if w.size+int64(len(p)) > maxLogSize {
+		if err := w.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rotating log file: %v\n", err)
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return len(p), err
+}
+
+// rotate renames the log files up by one, dropping the oldest, and starts a new file.
+// The caller holds w.mu.
+func (w *logWriter) rotate() error {
+	w.file.Close()
+	w.file = nil
+	current := filepath.Join(w.dir, logFileName)
+	os.Remove(fmt.Sprintf("%s.%d", current, logFilesKept))
+	for i := logFilesKept - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", current, i), fmt.Sprintf("%s.%d", current, i+1))
+	}
+	if err := os.Rename(current, current+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return w.open()
+}
+
+// open opens the current log file for appending. The caller holds w.mu.
+func (w *logWriter) open() error {
+	file, err := os.OpenFile(filepath.Join(w.dir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// files returns the log files, oldest first.
+func (w *logWriter) files() []string {
+	w.mu.Lock()
+	dir := w.dir
+	w.mu.Unlock()
+	if dir == "" {
+		return nil
+	}
+	var files []string
+	for i := logFilesKept; i >= 1; i-- {
+		path := filepath.Join(dir, fmt.Sprintf("%s.%d", logFileName, i))
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return append(files, filepath.Join(dir, logFileName))
+}
+
+// logDir returns the directory the log files are written to.
+func logDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "PolyField-Track", "logs"), nil
+}
+
+// openLogFile starts writing the log to the log directory as well as the console.
+// Errors are reported on the console and leave console logging running.
+func openLogFile() {
+	dir, err := logDir()
+	if err == nil {
+		err = os.MkdirAll(dir, 0755)
+	}
+	if err != nil {
+		appLog.Error("Failed to create log directory", "error", err)
+		return
+	}
+	logSink.mu.Lock()
+	logSink.dir = dir
+	err = logSink.open()
+	logSink.mu.Unlock()
+	if err != nil {
+		appLog.Error("Failed to open log file", "error", err)
+		return
+	}
+	appLog.Info("Logging to file", "path", filepath.Join(dir, logFileName), "logLevel", logLevelName())
+}
+
+// logLevelName returns the current log level: 'debug', 'info', 'warn' or 'error'.
+func logLevelName() string {
+	return strings.ToLower(logLevel.Level().String())
+}
+
+// setLogLevel changes the log level from its name.
+func setLogLevel(name string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+	}
+	logLevel.Set(level)
+	return nil
+}
+
+// GetLogLevel returns the current log level.
+func (a *App) GetLogLevel() string {
+	return logLevelName()
+}
+
+// SetLogLevel changes the log level. 'debug' includes every row read from every
+// result file, so is only useful while investigating a problem.
+func (a *App) SetLogLevel(level string) error {
+	if err := setLogLevel(level); err != nil {
+		return err
+	}
+	appLog.Info("Log level changed", "logLevel", logLevelName())
+	a.scheduleSave()
+	return nil
+}
+
+// GetDebugLogs returns the most recent log lines, oldest first.
+func (a *App) GetDebugLogs() []string {
+	logSink.mu.Lock()
+	defer logSink.mu.Unlock()
+	lines := make([]string, len(logSink.recent))
+	copy(lines, logSink.recent)
+	return lines
+}
+
+// writeLogArchive writes a zip of the log files and a summary of the system to out.
+func writeLogArchive(out io.Writer) error {
+	archive := zip.NewWriter(out)
+	info, err := archive.Create("system.txt")
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	fmt.Fprintf(info, "PolyField Track %s\nOS: %s/%s\nGo: %s\nHost: %s\nLog level: %s\nExported: %s\n",
+		appVersion, runtime.GOOS, runtime.GOARCH, runtime.Version(), hostname, logLevelName(), time.Now().Format(time.RFC3339))
+	for _, path := range logSink.files() {
+		if err := addFileToZip(archive, path, filepath.Base(path)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// addFileToZip copies the file at path into the archive as name.
+func addFileToZip(archive *zip.Writer, path string, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// logArchiveName is the suggested file name for exported logs.
+func logArchiveName() string {
+	return fmt.Sprintf("PolyField-Track-logs_%s.zip", time.Now().Format("20060102-150405"))
+}
+
+// ExportLogs asks where to save the log files and writes them there as a zip, for
+// sending to support. It returns the path written, or an empty string if cancelled.
+func (a *App) ExportLogs() (string, error) {
+	path, err := wailsruntime.SaveFileDialog(a.ctx, wailsruntime.SaveDialogOptions{
+		Title:           "Export Logs",
+		DefaultFilename: logArchiveName(),
+		Filters:         []wailsruntime.FileFilter{{DisplayName: "Zip files", Pattern: "*.zip"}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to export logs: %v", err)
+	}
+	if err := writeLogArchive(file); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to export logs: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to export logs: %v", err)
+	}
+	appLog.Info("Logs exported", "path", path)
+	return path, nil
+}
+
+// registerLogRoutes adds the log level and export endpoints to the Fiber server.
+func registerLogRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/logs", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "application/zip")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, logArchiveName()))
+		return writeLogArchive(c.Response().BodyWriter())
+	})
+	fiberApp.Get("/logs/level", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		return c.JSON(map[string]interface{}{"level": app.GetLogLevel()})
+	})
+	fiberApp.Post("/logs/level", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetLogLevel(req.Level); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
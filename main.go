@@ -53,20 +53,30 @@ type Competitor struct {
 type LifData struct {
 	FileName     string       `json:"fileName"`
 	EventName    string       `json:"eventName"`
-	Wind         string       `json:"wind"` // Wind with unit "m/s" if provided
+	Round        string       `json:"round,omitempty"` // Round number from the event row, if given
+	Heat         string       `json:"heat,omitempty"`  // Heat number from the event row, if given
+	Wind         string       `json:"wind"`            // Wind with unit "m/s" if provided
 	Competitors  []Competitor `json:"competitors"`
+	DNS          []Competitor `json:"dns,omitempty"` // Competitors who didn't start, left out of Competitors
 	ModifiedTime int64        `json:"modifiedTime"`
+	Amended      bool         `json:"amended"`            // True if the file was re-saved with changes since monitoring started
+	Warnings     []RowIssue   `json:"warnings,omitempty"` // Rows skipped for a reason other than DNS
 }
 
 // DisplayState holds the current display mode and settings
 type DisplayState struct {
-	Mode         string   `json:"mode"`         // 'lif', 'text', or 'screensaver'
-	ActiveText   string   `json:"activeText"`   // Text to display
-	ImageBase64  string   `json:"imageBase64"`  // Base64 encoded image for screensaver
-	RotationMode string   `json:"rotationMode"` // 'scroll', 'page', or 'scrollAll'
-	LayoutTheme  string   `json:"layoutTheme"`  // 'classic', 'modernDark', 'light', or 'highContrast'
-	CurrentLIF   *LifData `json:"currentLIF"`   // Current single event LIF for full screen mode
-	ShowBib      bool     `json:"showBib"`      // Whether to show bib column in tables
+	Mode              string   `json:"mode"`              // 'lif', 'text', or 'screensaver'
+	ActiveText        string   `json:"activeText"`        // Text to display
+	ImageBase64       string   `json:"imageBase64"`       // Base64 encoded image posted by older control panels; stored in the media library and replaced by ImageID
+	RotationMode      string   `json:"rotationMode"`      // 'scroll', 'page', or 'scrollAll'
+	LayoutTheme       string   `json:"layoutTheme"`       // 'classic', 'modernDark', 'light', or 'highContrast'
+	CurrentLIF        *LifData `json:"currentLIF"`        // Current single event LIF for full screen mode
+	ShowBib           bool     `json:"showBib"`           // Whether to show bib column in tables
+	ImageID           string   `json:"imageId"`           // Media library ID of the screensaver image
+	SlideshowIDs      []string `json:"slideshowIds"`      // Media library IDs the screensaver rotates through, if set
+	SlideshowInterval int      `json:"slideshowInterval"` // Seconds each slideshow image is shown for
+	View              string   `json:"view"`              // 'multi', 'fullscreen' or 'league' when set by a playlist, otherwise empty
+	Playlist          string   `json:"playlist"`          // Name of the playlist running on this channel, if any
 }
 
 // App holds the application state.
@@ -76,120 +86,147 @@ type App struct {
 	monitoredDir       string
 	latestData         *LifData
 	watcher            *fsnotify.Watcher
-	displayState       *DisplayState
-	customClubAcronyms map[string]string // lowercased full name -> acronym
+	channels           map[string]*DisplayState // display channel name -> state
+	customClubAcronyms map[string]string        // lowercased full name -> acronym
+	overrides          []ResultOverride         // manual corrections applied after every parse
+	overridesMu        sync.Mutex               // serialises changes to the overrides file
+	lastResults        map[string]*LifData      // file name -> last parsed result, for amendment diffs
+	amendments         []*ResultDiff
+	amended            map[string]bool // file name -> amended since monitoring started
+	holdForApproval    bool            // hold new results in the pending queue until approved
+	pending            []*PendingResult
+	pendingSeq         int
+	approved           map[string]*LifData          // file name -> approved result, used while holding for approval
+	approvedHashes     map[string]string            // file name -> hash of the approved file contents
+	rejected           map[string]string            // file name -> hash of the rejected file contents
+	approvalsMu        sync.Mutex                   // serialises writes of the approvals file
+	operatorToken      string                       // desktop app's admin token, see GetOperatorToken
+	accessKeys         []*AccessKey                 // PINs and tokens for web control panels
+	sessions           map[string]*authSession      // session cookie -> logged in control panel
+	loginAttempts      map[string]*loginAttempts    // address -> failed logins
+	displays           map[string]*ConnectedDisplay // display id -> registered web display
+	playlists          map[string]*Playlist         // playlist name -> playlist
+	playlistRuns       map[string]*playlistRun      // display channel name -> playlist position
+	mediaMu            sync.Mutex
+	media              map[string]*MediaItem // media library id -> item
+	saveMu             sync.Mutex
+	saveTimer          *time.Timer // pending settings save, see scheduleSave
+	settingsFile       string      // settings file given with -config in headless mode
+	serverMu           sync.Mutex  // serialises web server restarts
+	server             *fiber.App
+	listenAddress      string       // configured listen address, see serverAddress
+	port               int          // configured HTTP port, see serverAddress
+	activePort         int          // HTTP port in use, after falling back from a taken port
+	httpsEnabled       bool         // also serve over HTTPS with a local CA certificate
+	httpsListener      net.Listener // nil when HTTPS isn't running
+	activeHTTPSPort    int
+	mdnsMu             sync.Mutex // serialises mDNS registration, see startMDNS
+	mdnsServer         *mdns.Server
+	mdnsKey            string // current registration, see mdnsServiceKey
+	instanceID         string // identifies this instance in mDNS discovery
+	instanceName       string // advertised mDNS instance name, see GetMDNSSettings
+	mdnsHostname       string // advertised mDNS hostname, see GetMDNSSettings
+	replication        ReplicationSettings
+	replica            replicationState
+	auditMu            sync.Mutex   // guards the audit journal, see audit.go
+	meet               *Meet        // nil until the meet details are set
+	archive            *meetArchive // meet archive being browsed instead of the monitored directory, see loadArchive
+	metrics            serverMetrics
+	parseCacheMu       sync.Mutex
+	parseCache         map[string]cachedParse    // result file path -> last parse, see parseResultCached
+	watching           *fsnotify.Watcher         // set while watchDirectory is running
+	auditDir           string                    // directory the journal state below was loaded from
+	auditHashes        map[string]string         // file name -> hash last recorded in the journal
+	auditDisplayed     map[string]displaySummary // display channel name -> state last recorded
 }
 
 // NewApp creates a new App instance.
 func NewApp() *App {
 	return &App{
-		displayState: &DisplayState{
-			Mode:         "lif",
-			ActiveText:   "",
-			ImageBase64:  "",
-			RotationMode: "scroll",
-			LayoutTheme:  "classic",
-			ShowBib:      true,
-		},
+		channels:           map[string]*DisplayState{defaultChannel: newDisplayState()},
 		customClubAcronyms: make(map[string]string),
+		lastResults:        make(map[string]*LifData),
+		amended:            make(map[string]bool),
+		operatorToken:      randomToken(),
+		instanceID:         randomToken(),
+		sessions:           make(map[string]*authSession),
+		loginAttempts:      make(map[string]*loginAttempts),
+		displays:           make(map[string]*ConnectedDisplay),
+		playlists:          make(map[string]*Playlist),
+		playlistRuns:       make(map[string]*playlistRun),
+		media:              make(map[string]*MediaItem),
+		metrics:            serverMetrics{started: time.Now()},
+		parseCache:         make(map[string]cachedParse),
+		auditHashes:        make(map[string]string),
+		auditDisplayed:     make(map[string]displaySummary),
 	}
 }
 
-// SetDisplayState updates the current display state (called from frontend)
+// SetDisplayState updates the current display state of the default channel (called from frontend)
 func (a *App) SetDisplayState(mode string, text string, imageBase64 string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		a.displayState = &DisplayState{}
-	}
-	a.displayState.Mode = mode
-	a.displayState.ActiveText = text
-	a.displayState.ImageBase64 = imageBase64
-	log.Printf("Display state updated: mode=%s", mode)
+	image := DisplayState{ImageBase64: imageBase64}
+	keepImage := a.resolveDisplayImage(&image)
+	a.updateChannel(sourceDesktop, defaultChannel, func(state *DisplayState) {
+		state.Mode = mode
+		state.ActiveText = text
+		if keepImage {
+			state.ImageID = image.ImageID
+		}
+	})
+	displayLog.Debug("Display state updated", "mode", mode)
 }
 
 // SetCurrentLIF updates the current LIF data for full screen display (called from frontend)
 func (a *App) SetCurrentLIF(lifData *LifData) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		a.displayState = &DisplayState{}
-	}
-	a.displayState.CurrentLIF = lifData
+	a.updateChannel(sourceDesktop, defaultChannel, func(state *DisplayState) {
+		state.CurrentLIF = lifData
+	})
 	if lifData != nil {
-		log.Printf("Current LIF updated: %s", lifData.EventName)
+		displayLog.Debug("Current LIF updated", "event", lifData.EventName)
 	} else {
-		log.Printf("Current LIF cleared")
+		displayLog.Debug("Current LIF cleared")
 	}
 }
 
 // SetRotationMode updates the rotation mode (called from frontend)
 func (a *App) SetRotationMode(rotationMode string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		a.displayState = &DisplayState{
-			Mode:         "lif",
-			ActiveText:   "",
-			ImageBase64:  "",
-			RotationMode: rotationMode,
-		}
-	} else {
-		a.displayState.RotationMode = rotationMode
-	}
-	log.Printf("Rotation mode updated: %s", rotationMode)
+	a.updateChannel(sourceDesktop, defaultChannel, func(state *DisplayState) {
+		state.RotationMode = rotationMode
+	})
+	displayLog.Debug("Rotation mode updated", "rotationMode", rotationMode)
 }
 
 // SetLayoutTheme updates the layout theme (called from frontend)
 func (a *App) SetLayoutTheme(layoutTheme string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		a.displayState = &DisplayState{
-			Mode:         "lif",
-			ActiveText:   "",
-			ImageBase64:  "",
-			RotationMode: "scroll",
-			LayoutTheme:  layoutTheme,
-		}
-	} else {
-		a.displayState.LayoutTheme = layoutTheme
-	}
-	log.Printf("Layout theme updated: %s", layoutTheme)
+	a.updateChannel(sourceDesktop, defaultChannel, func(state *DisplayState) {
+		state.LayoutTheme = layoutTheme
+	})
+	displayLog.Debug("Layout theme updated", "layoutTheme", layoutTheme)
 }
 
 // SetShowBib updates the show bib setting (called from frontend)
 func (a *App) SetShowBib(show bool) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		a.displayState = &DisplayState{
-			Mode:         "lif",
-			ActiveText:   "",
-			ImageBase64:  "",
-			RotationMode: "scroll",
-			LayoutTheme:  "classic",
-			ShowBib:      show,
-		}
-	} else {
-		a.displayState.ShowBib = show
-	}
-	log.Printf("Show bib updated: %v", show)
+	a.updateChannel(sourceDesktop, defaultChannel, func(state *DisplayState) {
+		state.ShowBib = show
+	})
+	displayLog.Debug("Show bib updated", "showBib", show)
 }
 
-// GetDisplayState returns the current display state
+// GetDisplayState returns the current display state of the default channel
 func (a *App) GetDisplayState() *DisplayState {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if a.displayState == nil {
-		return &DisplayState{Mode: "lif", ActiveText: "", ImageBase64: "", RotationMode: "scroll", LayoutTheme: "classic", ShowBib: true}
+	return a.GetChannelDisplayState(defaultChannel)
+}
+
+// emitEvent sends an event to the desktop frontend, if the Wails runtime is running.
+func (a *App) emitEvent(name string, data ...interface{}) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, name, data...)
 	}
-	return a.displayState
 }
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
-	log.Println("Wails app startup complete. Context set.")
+	appLog.Info("Desktop app started")
 	// Maximize window on startup.
 	runtime.WindowMaximise(a.ctx)
 }
@@ -199,18 +236,43 @@ func (a *App) ChooseDirectory() (string, error) {
 		Title: "Select Directory to Monitor",
 	})
 	if err != nil {
-		log.Println("OpenDirectoryDialog error:", err)
+		appLog.Error("Directory dialog failed", "error", err)
 		return "", err
 	}
 	if dir == "" {
-		log.Println("No directory selected (user canceled)")
+		appLog.Debug("No directory selected")
 		return "", nil
 	}
-	log.Println("Directory selected:", dir)
+	appLog.Info("Directory selected", "dir", dir)
+	a.openDirectory(dir)
+	a.scheduleSave()
+	return dir, nil
+}
+
+// openDirectory starts monitoring dir, replacing any directory already being monitored.
+func (a *App) openDirectory(dir string) {
+	a.mu.Lock()
+	if a.watcher != nil {
+		// Closing the watcher ends the previous watchDirectory goroutine.
+		a.watcher.Close()
+		a.watcher = nil
+	}
+	if a.monitoredDir != "" && a.monitoredDir != dir {
+		// Another folder is another meet, unless its meet settings file says otherwise.
+		a.meet = nil
+	}
 	a.monitoredDir = dir
+	a.mu.Unlock()
+	a.clearParseCache()
+	a.loadMeetSettings(dir)
 	a.initClubList()
+	a.initOverrides()
+	a.initApprovals()
+	a.loadAudit(dir)
+	a.auditDisplays(sourceApp)
 	go a.watchDirectory()
-	return dir, nil
+	// Advertise the meet in this directory.
+	go a.startMDNS()
 }
 
 // SaveGraphic saves a base64-encoded PNG image to the monitored directory.
@@ -241,7 +303,7 @@ func (a *App) SaveGraphic(base64Data string, units string) (string, error) {
 		return "", fmt.Errorf("failed to save graphic: %v", err)
 	}
 
-	log.Printf("Graphic saved: %s", fullPath)
+	appLog.Info("Graphic saved", "path", fullPath)
 	return fullPath, nil
 }
 
@@ -278,7 +340,7 @@ func (a *App) generateClubListCSV(dir string) error {
 		}
 	}
 
-	log.Printf("Generated club-list.csv with %d entries in %s", len(defaultClubAcronyms), dir)
+	appLog.Info("Generated club-list.csv", "entries", len(defaultClubAcronyms), "dir", dir)
 	return nil
 }
 
@@ -310,7 +372,7 @@ func loadClubListCSV(dir string) (map[string]string, error) {
 		}
 	}
 
-	log.Printf("Loaded %d custom club acronyms from club-list.csv", len(result))
+	appLog.Info("Loaded custom club acronyms from club-list.csv", "count", len(result))
 	return result, nil
 }
 
@@ -323,14 +385,14 @@ func (a *App) initClubList() {
 	csvPath := filepath.Join(a.monitoredDir, "club-list.csv")
 	if _, err := os.Stat(csvPath); os.IsNotExist(err) {
 		if err := a.generateClubListCSV(a.monitoredDir); err != nil {
-			log.Printf("Error generating club-list.csv: %v", err)
+			appLog.Error("Failed to generate club-list.csv", "error", err)
 			return
 		}
 	}
 
 	acronyms, err := loadClubListCSV(a.monitoredDir)
 	if err != nil {
-		log.Printf("Error loading club-list.csv: %v", err)
+		appLog.Error("Failed to load club-list.csv", "error", err)
 		return
 	}
 
@@ -342,17 +404,30 @@ func (a *App) initClubList() {
 func (a *App) watchDirectory() {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		log.Println("Error creating watcher:", err)
+		watcherLog.Error("Failed to create watcher", "error", err)
 		return
 	}
+	a.mu.Lock()
 	a.watcher = watcher
+	a.mu.Unlock()
 
 	err = watcher.Add(a.monitoredDir)
 	if err != nil {
-		log.Println("Error adding directory to watcher:", err)
+		watcherLog.Error("Failed to watch directory", "dir", a.monitoredDir, "error", err)
 		return
 	}
-	log.Println("Monitoring directory:", a.monitoredDir)
+	watcherLog.Info("Monitoring directory", "dir", a.monitoredDir)
+	a.mu.Lock()
+	a.watching = watcher
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		if a.watching == watcher {
+			a.watching = nil
+		}
+		a.mu.Unlock()
+	}()
+	a.seedResults()
 
 	for {
 		select {
@@ -363,49 +438,106 @@ func (a *App) watchDirectory() {
 			// Handle club-list.csv changes
 			if filepath.Base(event.Name) == "club-list.csv" &&
 				(event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create) {
-				log.Println("Detected change in club-list.csv, reloading...")
+				watcherLog.Info("Detected change in club-list.csv, reloading")
 				time.Sleep(100 * time.Millisecond)
 				acronyms, err := loadClubListCSV(a.monitoredDir)
 				if err != nil {
-					log.Printf("Error reloading club-list.csv: %v", err)
+					watcherLog.Error("Failed to reload club-list.csv", "error", err)
 				} else {
 					a.mu.Lock()
 					a.customClubAcronyms = acronyms
 					a.mu.Unlock()
-					log.Printf("Reloaded %d custom club acronyms", len(acronyms))
+					watcherLog.Info("Reloaded custom club acronyms", "count", len(acronyms))
 				}
 				continue
 			}
-			ext := strings.ToLower(filepath.Ext(event.Name))
-			if (ext == ".lif" || ext == ".res" || ext == ".txt") &&
+			// Handle overrides file changes
+			if filepath.Base(event.Name) == overridesFile &&
+				(event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create) {
+				time.Sleep(100 * time.Millisecond)
+				a.initOverrides()
+				continue
+			}
+			if isResultFile(event.Name) &&
+				(event.Op&fsnotify.Remove == fsnotify.Remove || event.Op&fsnotify.Rename == fsnotify.Rename) {
+				a.forgetParse(event.Name)
+				continue
+			}
+			if isResultFile(event.Name) &&
 				(event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create) {
-				log.Println("Detected change in:", event.Name)
+				watcherLog.Info("Detected change", "file", filepath.Base(event.Name))
 				time.Sleep(100 * time.Millisecond)
-				data, err := parseFile(event.Name)
+				data, err := a.parseResult(event.Name)
+				a.auditResultFile(event.Name, data, err)
 				if err != nil {
-					log.Printf("Error parsing %s file: %v", ext, err)
+					watcherLog.Error("Failed to parse result file", "file", filepath.Base(event.Name), "error", err)
+					a.emitEvent("parse-problem", filepath.Base(event.Name))
+					continue
+				}
+				if len(data.Warnings) > 0 {
+					watcherLog.Warn("Rows skipped", "file", data.FileName, "count", len(data.Warnings))
+					a.emitEvent("parse-problem", data.FileName)
+				}
+				a.metrics.recordResult(time.Now())
+				diff := a.recordResult(data)
+				data.Amended = a.isAmended(data.FileName)
+				if a.GetHoldForApproval() {
+					hash, _ := fileHash(event.Name)
+					a.queuePending(data, diff, hash)
 					continue
 				}
 				a.mu.Lock()
 				a.latestData = data
 				a.mu.Unlock()
+				a.interruptPlaylists(data)
+				a.auditDisplays(sourceWatcher)
 			}
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
 			}
-			log.Println("Watcher error:", err)
+			a.metrics.recordWatcherError()
+			watcherLog.Error("Watcher error", "error", err)
 		}
 	}
 }
 
 // GetAllLIFData scans the monitored directory for all .lif, .res, and .txt files,
-// parses each file fresh, and returns a slice of pointers to LifData.
-// It does not retain previous data.
+// parses each file that has changed since it was last parsed, and returns a slice
+// of pointers to LifData. While holding for approval, only approved results are
+// returned.
 func (a *App) GetAllLIFData() ([]*LifData, error) {
+	if results := a.archivedResults(); results != nil {
+		// An open archive replaces the monitored directory.
+		return results, nil
+	}
+	if results := a.replicatedResults(); results != nil {
+		// A backup serves the results mirrored from the primary.
+		return results, nil
+	}
 	if a.monitoredDir == "" {
 		return nil, fmt.Errorf("no directory selected")
 	}
+	var results []*LifData
+	if a.GetHoldForApproval() {
+		// Re-apply overrides so corrections made after approval are shown.
+		for _, approved := range a.approvedResults() {
+			data := *approved
+			a.applyOverrides(&data)
+			results = append(results, &data)
+		}
+	} else {
+		var err error
+		results, err = a.scanResults()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dedupeResults(results), nil
+}
+
+// scanResults parses every result file in the monitored directory.
+func (a *App) scanResults() ([]*LifData, error) {
 	entries, err := os.ReadDir(a.monitoredDir)
 	if err != nil {
 		return nil, err
@@ -413,18 +545,32 @@ func (a *App) GetAllLIFData() ([]*LifData, error) {
 	var results []*LifData
 	for _, entry := range entries {
 		if !entry.IsDir() {
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
-			if ext == ".lif" || ext == ".res" || ext == ".txt" {
+			if isResultFile(entry.Name()) {
 				filePath := filepath.Join(a.monitoredDir, entry.Name())
-				data, err := parseFile(filePath)
+				if hash, err := fileHash(filePath); err == nil && a.heldBack(entry.Name(), hash) {
+					// Pending and rejected saves stay unpublished; show the approved version, if any.
+					if approved := a.approvedResult(entry.Name()); approved != nil {
+						approved.Amended = a.isAmended(approved.FileName)
+						results = append(results, approved)
+					}
+					continue
+				}
+				data, err := a.parseResult(filePath)
 				if err != nil {
-					log.Printf("Error parsing %s file %s: %v", ext, entry.Name(), err)
+					// Reported by the watcher when the file changes, and by /diagnostics.
+					parserLog.Debug("Failed to parse result file", "file", entry.Name(), "error", err)
 					continue
 				}
+				data.Amended = a.isAmended(data.FileName)
 				results = append(results, data)
 			}
 		}
 	}
+	return results, nil
+}
+
+// dedupeResults removes results with identical competitors and sorts them by ModifiedTime.
+func dedupeResults(results []*LifData) []*LifData {
 	// Remove duplicates based on competitor data
 	// If two files have identical competitors (same athletes and performances), keep only the newer one
 	deduplicated := make([]*LifData, 0, len(results))
@@ -457,20 +603,21 @@ func (a *App) GetAllLIFData() ([]*LifData, error) {
 	sort.Slice(deduplicated, func(i, j int) bool {
 		return deduplicated[i].ModifiedTime < deduplicated[j].ModifiedTime
 	})
-	return deduplicated, nil
+	return deduplicated
 }
 
 // getDecoder now uses the chardet package to determine the file's encoding.
 // The original file is only read (not modified) and its contents are converted to UTF‑8.
-func getDecoder(file *os.File) (transform.Transformer, error) {
+// It also returns the detected charset, or an empty string if detection failed.
+func getDecoder(file *os.File) (transform.Transformer, string, error) {
 	const sampleSize = 512
 	buf := make([]byte, sampleSize)
 	n, err := file.Read(buf)
 	if err != nil && err != io.EOF {
-		return nil, err
+		return nil, "", err
 	}
 	if _, err := file.Seek(0, 0); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	sample := buf[:n]
 
@@ -478,26 +625,26 @@ func getDecoder(file *os.File) (transform.Transformer, error) {
 	detector := chardet.NewTextDetector()
 	result, err := detector.DetectBest(sample)
 	if err != nil {
-		log.Printf("Error detecting charset, defaulting to no transformation: %v", err)
-		return transform.Nop, nil
+		parserLog.Warn("Failed to detect charset, reading as UTF-8", "file", filepath.Base(file.Name()), "error", err)
+		return transform.Nop, "", nil
 	}
-	log.Printf("Detected charset: %s", result.Charset)
+	parserLog.Debug("Detected charset", "file", filepath.Base(file.Name()), "charset", result.Charset)
 
 	// Return the appropriate decoder based on the detected charset.
 	switch strings.ToLower(result.Charset) {
 	case "utf-8":
-		return transform.Nop, nil
+		return transform.Nop, result.Charset, nil
 	case "windows-1252":
-		return charmap.Windows1252.NewDecoder(), nil
+		return charmap.Windows1252.NewDecoder(), result.Charset, nil
 	case "iso-8859-1":
-		return charmap.ISO8859_1.NewDecoder(), nil
+		return charmap.ISO8859_1.NewDecoder(), result.Charset, nil
 	case "utf-16le":
-		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder(), nil
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder(), result.Charset, nil
 	case "utf-16be":
-		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder(), nil
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder(), result.Charset, nil
 	default:
-		log.Printf("Charset %s not explicitly handled, defaulting to no transformation", result.Charset)
-		return transform.Nop, nil
+		parserLog.Warn("Charset not handled, reading as UTF-8", "file", filepath.Base(file.Name()), "charset", result.Charset)
+		return transform.Nop, result.Charset + " (not converted)", nil
 	}
 }
 
@@ -591,7 +738,7 @@ func cleanTimeString(s string) string {
 	}, s)
 }
 
-func parseResFile(path string) (*LifData, error) {
+func parseResFile(path string, report *ParseReport) (*LifData, error) {
 	// Check if this is a .txt file for special cleaning
 	isTxtFile := strings.ToLower(filepath.Ext(path)) == ".txt"
 
@@ -600,10 +747,11 @@ func parseResFile(path string) (*LifData, error) {
 		return nil, err
 	}
 	defer file.Close()
-	decoder, err := getDecoder(file)
+	decoder, charset, err := getDecoder(file)
 	if err != nil {
 		return nil, err
 	}
+	report.Charset = charset
 	utf8Reader := transform.NewReader(file, decoder)
 	reader := csv.NewReader(utf8Reader)
 	reader.Comma = '\t' // TAB delimiter for .res files
@@ -612,11 +760,12 @@ func parseResFile(path string) (*LifData, error) {
 	if err != nil {
 		return nil, err
 	}
+	report.Rows = len(records)
 	if len(records) < 3 {
 		return nil, fmt.Errorf("insufficient records in file: %s (expected at least 3 lines)", path)
 	}
 	for i, row := range records {
-		log.Printf("Row %d (fields: %d): %v", i, len(row), row)
+		parserLog.Debug("Row read", "file", report.FileName, "row", i, "fields", len(row), "values", row)
 	}
 
 	// Line 0: Image information line (contains filename, wind, file size, lines per second, time and date)
@@ -673,30 +822,20 @@ func parseResFile(path string) (*LifData, error) {
 	// Line 2+: Competitor data
 	// Fields: Place, Lane, Time, ID, Name (optional), Extra info (optional)
 
-	var competitors []Competitor
+	var competitors, dns []Competitor
 	for i := 2; i < len(records); i++ {
 		row := records[i]
 		// .res files have at least 3 fields (place, lane, time) and up to 6 fields
 		if len(row) < 3 {
-			log.Printf("Row %d skipped: not enough fields (found %d, expected at least 3)", i, len(row))
+			parserLog.Debug("Row skipped: not enough fields", "file", report.FileName, "row", i, "fields", len(row), "expected", 3)
+			report.skip(i, issueFields, fmt.Sprintf("not enough fields (found %d, expected at least 3)", len(row)), row)
 			continue
 		}
 
 		place := strings.TrimSpace(row[0])
 
-		// Skip DNS entries entirely - they should not be displayed
-		if place == "" || strings.ToUpper(place) == "DNS" {
-			log.Printf("Row %d skipped: DNS entry or empty place '%s'", i, place)
-			continue
-		}
-
 		// Field 1 is lane (not used in the display)
 
-		rawTime := ""
-		if len(row) > 2 {
-			rawTime = cleanTimeString(strings.TrimSpace(row[2]))
-		}
-
 		id := ""
 		if len(row) > 3 {
 			id = strings.TrimSpace(row[3])
@@ -751,6 +890,22 @@ func parseResFile(path string) (*LifData, error) {
 			}
 		}
 
+		// Leave DNS entries out of the results - they should not be displayed
+		if place == "" || strings.ToUpper(place) == "DNS" {
+			parserLog.Debug("Row skipped: DNS entry or empty place", "file", report.FileName, "row", i, "place", place)
+			report.skip(i, issueDNS, fmt.Sprintf("DNS entry or empty place '%s'", place), row)
+			if place != "" {
+				// Kept for exports, which list them with the DNS status.
+				dns = append(dns, Competitor{ID: id, FirstName: firstName, LastName: lastName, Affiliation: affiliation})
+			}
+			continue
+		}
+
+		rawTime := ""
+		if len(row) > 2 {
+			rawTime = cleanTimeString(strings.TrimSpace(row[2]))
+		}
+
 		var formattedTime string
 		upperPlace := strings.ToUpper(strings.TrimSpace(place))
 		upperTime := strings.ToUpper(rawTime)
@@ -764,13 +919,16 @@ func parseResFile(path string) (*LifData, error) {
 				formattedTime = "DNF"
 			}
 			place = "" // Clear place for DQ/DNF entries
+			report.note(i, issueStatus, formattedTime+": place cleared, listed after timed results", row)
 		} else if rawTime == "" {
-			log.Printf("Row %d skipped: no time value", i)
+			parserLog.Debug("Row skipped: no time value", "file", report.FileName, "row", i)
+			report.skip(i, issueNoTime, "no time value", row)
 			continue
 		} else {
 			formattedTime, err = roundAndFormatTime(rawTime)
 			if err != nil {
-				log.Printf("Row %d skipped: error processing time '%s': %v", i, rawTime, err)
+				parserLog.Debug("Row skipped: error processing time", "file", report.FileName, "row", i, "time", rawTime, "error", err)
+				report.skip(i, issueTime, fmt.Sprintf("error processing time '%s': %v", rawTime, err), row)
 				continue
 			}
 		}
@@ -790,6 +948,25 @@ func parseResFile(path string) (*LifData, error) {
 		return nil, fmt.Errorf("no valid competitor data found in file: %s", path)
 	}
 
+	competitors = orderCompetitors(competitors)
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %v", err)
+	}
+	data := &LifData{
+		FileName:     filepath.Base(path),
+		EventName:    eventName,
+		Wind:         wind,
+		Competitors:  competitors,
+		DNS:          dns,
+		ModifiedTime: fileInfo.ModTime().Unix(),
+	}
+	return data, nil
+}
+
+// orderCompetitors sorts timed competitors by time and puts DQ/DNF entries at the end.
+func orderCompetitors(competitors []Competitor) []Competitor {
 	// Split into timed and untimed (DQ/DNF)
 	var timed, untimed []Competitor
 	for _, c := range competitors {
@@ -806,46 +983,59 @@ func parseResFile(path string) (*LifData, error) {
 		return ti < tj
 	})
 	// Combine: timed first, then untimed (DQ/DNF at the end)
-	competitors = append(timed, untimed...)
+	return append(timed, untimed...)
+}
 
-	fileInfo, err := os.Stat(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %v", err)
-	}
-	data := &LifData{
-		FileName:     filepath.Base(path),
-		EventName:    eventName,
-		Wind:         wind,
-		Competitors:  competitors,
-		ModifiedTime: fileInfo.ModTime().Unix(),
+// isResultFile reports whether a file name has one of the supported result extensions.
+func isResultFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".lif", ".res", ".txt":
+		return true
 	}
-	return data, nil
+	return false
 }
 
 // parseFile determines the file type by extension and calls the appropriate parser
 func parseFile(path string) (*LifData, error) {
+	data, _, err := parseFileWithReport(path)
+	return data, err
+}
+
+// parseFileWithReport parses a result file like parseFile, also reporting the
+// detected charset and the rows that were skipped or changed. The report is
+// returned even when parsing fails.
+func parseFileWithReport(path string) (*LifData, *ParseReport, error) {
 	ext := strings.ToLower(filepath.Ext(path))
+	report := &ParseReport{FileName: filepath.Base(path), Format: strings.TrimPrefix(ext, ".")}
+	var data *LifData
+	var err error
 	switch ext {
 	case ".lif":
-		return parseLifFile(path)
+		data, err = parseLifFile(path, report)
 	case ".res", ".txt":
 		// Both .res and .txt use the same TAB-delimited format
-		return parseResFile(path)
+		data, err = parseResFile(path, report)
 	default:
-		return nil, fmt.Errorf("unsupported file type: %s", ext)
+		err = fmt.Errorf("unsupported file type: %s", ext)
 	}
+	if data != nil {
+		report.Competitors = len(data.Competitors)
+		data.Warnings = report.warnings()
+	}
+	return data, report, err
 }
 
-func parseLifFile(path string) (*LifData, error) {
+func parseLifFile(path string, report *ParseReport) (*LifData, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	decoder, err := getDecoder(file)
+	decoder, charset, err := getDecoder(file)
 	if err != nil {
 		return nil, err
 	}
+	report.Charset = charset
 	utf8Reader := transform.NewReader(file, decoder)
 	reader := csv.NewReader(utf8Reader)
 	reader.Comma = ','
@@ -854,15 +1044,21 @@ func parseLifFile(path string) (*LifData, error) {
 	if err != nil {
 		return nil, err
 	}
+	report.Rows = len(records)
 	if len(records) < 1 {
 		return nil, fmt.Errorf("no records found in file: %s", path)
 	}
 	for i, row := range records {
-		log.Printf("Row %d (fields: %d): %v", i, len(row), row)
+		parserLog.Debug("Row read", "file", report.FileName, "row", i, "fields", len(row), "values", row)
 	}
 	eventRow := records[0]
 	eventName := ""
+	round, heat := "", ""
 	wind := ""
+	if len(eventRow) >= 3 {
+		round = strings.TrimSpace(eventRow[1])
+		heat = strings.TrimSpace(eventRow[2])
+	}
 	if len(eventRow) >= 4 {
 		// Preserve the original spacing in the event name.
 		eventName = eventRow[3]
@@ -881,19 +1077,30 @@ func parseLifFile(path string) (*LifData, error) {
 			}
 		}
 	}
-	var competitors []Competitor
+	var competitors, dns []Competitor
 	for i := 1; i < len(records); i++ {
 		row := records[i]
 		// All LIF files are expected to have 7 fields in the competitor row.
 		if len(row) < 7 {
-			log.Printf("Row %d skipped: not enough fields (found %d)", i, len(row))
+			parserLog.Debug("Row skipped: not enough fields", "file", report.FileName, "row", i, "fields", len(row), "expected", 7)
+			report.skip(i, issueFields, fmt.Sprintf("not enough fields (found %d, expected 7)", len(row)), row)
 			continue
 		}
 		place := strings.TrimSpace(row[0])
 
-		// Skip DNS entries entirely - they should not be displayed
+		// Leave DNS entries out of the results - they should not be displayed
 		if place == "" || strings.ToUpper(place) == "DNS" {
-			log.Printf("Row %d skipped: DNS entry or empty place '%s'", i, place)
+			parserLog.Debug("Row skipped: DNS entry or empty place", "file", report.FileName, "row", i, "place", place)
+			report.skip(i, issueDNS, fmt.Sprintf("DNS entry or empty place '%s'", place), row)
+			if place != "" {
+				// Kept for exports, which list them with the DNS status.
+				dns = append(dns, Competitor{
+					ID:          strings.TrimSpace(row[1]),
+					FirstName:   strings.TrimSpace(row[4]),
+					LastName:    strings.TrimSpace(row[3]),
+					Affiliation: strings.TrimSpace(row[5]),
+				})
+			}
 			continue
 		}
 
@@ -911,10 +1118,12 @@ func parseLifFile(path string) (*LifData, error) {
 				formattedTime = "DNF"
 			}
 			place = "" // Clear place for DQ/DNF entries
+			report.note(i, issueStatus, formattedTime+": place cleared, listed after timed results", row)
 		} else {
 			formattedTime, err = roundAndFormatTime(rawTime)
 			if err != nil {
-				log.Printf("Row %d skipped: error processing time '%s': %v", i, rawTime, err)
+				parserLog.Debug("Row skipped: error processing time", "file", report.FileName, "row", i, "time", rawTime, "error", err)
+				report.skip(i, issueTime, fmt.Sprintf("error processing time '%s': %v", rawTime, err), row)
 				continue
 			}
 		}
@@ -933,23 +1142,7 @@ func parseLifFile(path string) (*LifData, error) {
 		return nil, fmt.Errorf("no valid competitor data found in file: %s", path)
 	}
 
-	// Split into timed and untimed (DQ/DNF)
-	var timed, untimed []Competitor
-	for _, c := range competitors {
-		if c.Time == "DQ" || c.Time == "DNF" {
-			untimed = append(untimed, c)
-		} else {
-			timed = append(timed, c)
-		}
-	}
-	// Sort timed by actual parsed time ascending
-	sort.Slice(timed, func(i, j int) bool {
-		ti, _ := parseTimeString(timed[i].Time)
-		tj, _ := parseTimeString(timed[j].Time)
-		return ti < tj
-	})
-	// Combine: timed first, then untimed (DQ/DNF at the end)
-	competitors = append(timed, untimed...)
+	competitors = orderCompetitors(competitors)
 
 	fileInfo, err := os.Stat(path)
 	if err != nil {
@@ -958,8 +1151,11 @@ func parseLifFile(path string) (*LifData, error) {
 	data := &LifData{
 		FileName:     filepath.Base(path),
 		EventName:    eventName,
+		Round:        round,
+		Heat:         heat,
 		Wind:         wind,
 		Competitors:  competitors,
+		DNS:          dns,
 		ModifiedTime: fileInfo.ModTime().Unix(),
 	}
 	return data, nil
@@ -983,7 +1179,16 @@ func getLocalIP() string {
 // GetWebInterfaceInfo returns a string with URLs to access the web interface.
 func (a *App) GetWebInterfaceInfo() string {
 	hostIP := getLocalIP()
-	return fmt.Sprintf("Access the web interface at: http://localhost:3000 or http://%s:3000", hostIP)
+	settings := a.GetServerSettings()
+	if settings.ListenAddress != defaultListenAddress {
+		hostIP = settings.ListenAddress
+	}
+	info := fmt.Sprintf("Access the web interface at: http://localhost:%d or http://%s:%d",
+		settings.ActivePort, hostIP, settings.ActivePort)
+	if settings.ActiveHTTPSPort != 0 {
+		info += fmt.Sprintf(" (HTTPS: https://%s:%d)", hostIP, settings.ActiveHTTPSPort)
+	}
+	return info
 }
 
 // getLANIPs returns the non-loopback IPv4 addresses of this machine.
@@ -1012,39 +1217,21 @@ func getLANIPs() []net.IP {
 	return ips
 }
 
-// startMDNS registers "track.local" via mDNS so LAN devices can reach the server.
-func startMDNS() {
-	ips := getLANIPs()
-	if len(ips) == 0 {
-		log.Println("mDNS: no LAN IP addresses found, skipping registration")
-		return
-	}
-	service, err := mdns.NewMDNSService(
-		"PolyField Track", // instance name
-		"_http._tcp",      // service type
-		"",                // domain (default "local.")
-		"track.local.",    // custom hostname
-		3000,              // port
-		ips,               // IP addresses
-		[]string{"path=/"},
-	)
-	if err != nil {
-		log.Printf("mDNS: failed to create service: %v", err)
-		return
-	}
-	_, err = mdns.NewServer(&mdns.Config{Zone: service})
-	if err != nil {
-		log.Printf("mDNS: failed to start server: %v", err)
-		return
-	}
-	log.Printf("mDNS: registered track.local -> %v", ips)
-}
-
-func StartFiberServer(app *App) {
-	fiberApp := fiber.New()
+// StartFiberServer builds the web server and starts listening. It returns once the
+// server is listening, or with an error if no port could be opened.
+func StartFiberServer(app *App) error {
+	fiberApp := fiber.New(fiber.Config{
+		// Bodies over the default limit are streamed instead of rejected, and multipart
+		// forms are only parsed by the handlers that ask for them, so limitBodies can let
+		// media uploads through to disk and turn every other large body away.
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
+	fiberApp.Use(app.countRequests)
+	fiberApp.Use(limitBodies)
 	fiberApp.Use(cors.New(cors.Config{
-		AllowOrigins:     "*",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowOriginsFunc: allowedOrigin,
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Operator-Token",
 		ExposeHeaders:    "Content-Length",
 		AllowCredentials: false,
 	}))
@@ -1052,6 +1239,12 @@ func StartFiberServer(app *App) {
 	fiberApp.Get("/latest-lif", func(c *fiber.Ctx) error {
 		app.mu.Lock()
 		data := app.latestData
+		if app.archive != nil {
+			data = nil
+			if results := app.archive.results; len(results) > 0 {
+				data = results[len(results)-1]
+			}
+		}
 		app.mu.Unlock()
 		if data == nil {
 			return c.JSON(map[string]interface{}{})
@@ -1059,40 +1252,87 @@ func StartFiberServer(app *App) {
 		return c.JSON(data)
 	})
 	// API endpoint to get all LIF data.
+	// ?session= picks the session: all, today, current or a session ID from /sessions.
+	// Without it the meet's display filter applies.
 	fiberApp.Get("/all-lif", func(c *fiber.Ctx) error {
 		data, err := app.GetAllLIFData()
 		if err != nil {
 			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
 		}
+		meet := app.GetMeet()
+		filter := c.Query("session", meet.DisplayFilter)
+		if data, err = meet.filterResults(data, filter, time.Now()); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
 		return c.JSON(data)
 	})
-	// API endpoint to get display state.
+	// API endpoint to get display state, scoped to a display channel with ?channel=.
 	fiberApp.Get("/display-state", func(c *fiber.Ctx) error {
-		state := app.GetDisplayState()
+		state := app.GetChannelDisplayState(c.Query("channel"))
 		return c.JSON(state)
 	})
-	// API endpoint to set display state.
-	fiberApp.Post("/display-state", func(c *fiber.Ctx) error {
+	// API endpoint to set display state, scoped to a display channel with ?channel=.
+	fiberApp.Post("/display-state", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
 		var state DisplayState
 		if err := c.BodyParser(&state); err != nil {
 			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
 		}
-		app.SetDisplayState(state.Mode, state.ActiveText, state.ImageBase64)
-		if state.RotationMode != "" {
-			app.SetRotationMode(state.RotationMode)
-		}
-		if state.LayoutTheme != "" {
-			app.SetLayoutTheme(state.LayoutTheme)
+		if err := app.setChannelDisplayState(app.requestSource(c), c.Query("channel"), state); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
 		}
-		app.SetShowBib(state.ShowBib)
-		app.SetCurrentLIF(state.CurrentLIF)
 		return c.JSON(map[string]interface{}{"success": true})
 	})
+	// Login and access key endpoints.
+	registerAuthRoutes(fiberApp, app)
+	// Display channel endpoints.
+	registerChannelRoutes(fiberApp, app)
+	// Connected display registry endpoints.
+	registerDisplayRoutes(fiberApp, app)
+	// Playlist endpoints.
+	registerPlaylistRoutes(fiberApp, app)
+	// League table endpoint.
+	registerLeagueRoutes(fiberApp, app)
+	// Media library endpoints.
+	registerMediaRoutes(fiberApp, app)
+	// API endpoint to get result amendments, optionally filtered by ?file=.
+	fiberApp.Get("/amendments", func(c *fiber.Ctx) error {
+		return c.JSON(app.GetAmendments(c.Query("file")))
+	})
+	// Approval queue endpoints.
+	registerApprovalRoutes(fiberApp, app)
+	// Result correction endpoints.
+	registerOverrideRoutes(fiberApp, app)
+	// Discovery of other instances on the LAN.
+	registerDiscoveryRoutes(fiberApp, app)
+	// Local CA certificate download for HTTPS.
+	registerTLSRoutes(fiberApp, app)
+	// Primary/backup replication endpoints.
+	registerReplicationRoutes(fiberApp, app)
+	// Settings endpoints, for controlling the server without the desktop app.
+	registerSettingsRoutes(fiberApp, app)
+	// Parse diagnostics endpoint.
+	registerDiagnosticsRoutes(fiberApp, app)
+	// Log level and log export endpoints.
+	registerLogRoutes(fiberApp, app)
+	// Meet details and session endpoints.
+	registerMeetRoutes(fiberApp, app)
+	// Meet archive endpoints.
+	registerArchiveRoutes(fiberApp, app)
+	// Rankings submission export endpoints.
+	registerRankingRoutes(fiberApp, app)
+	// Results spreadsheet export endpoints.
+	registerExportRoutes(fiberApp, app)
+	// PDF result sheet endpoints.
+	registerResultSheetRoutes(fiberApp, app)
+	// Static results site export endpoint.
+	registerSiteRoutes(fiberApp, app)
+	// Audit journal endpoint.
+	registerAuditRoutes(fiberApp, app)
+	// Health check and Prometheus metrics endpoints.
+	registerMetricsRoutes(fiberApp, app)
 	// API endpoint to get custom club acronyms.
 	fiberApp.Get("/club-acronyms", func(c *fiber.Ctx) error {
-		app.mu.Lock()
-		acronyms := app.customClubAcronyms
-		app.mu.Unlock()
+		acronyms := app.clubAcronyms()
 		if acronyms == nil {
 			return c.JSON(map[string]string{})
 		}
@@ -1117,16 +1357,73 @@ func StartFiberServer(app *App) {
 		c.Set("Content-Type", "text/html")
 		return c.Send(indexHTML)
 	})
-	// Listen on all interfaces (0.0.0.0) to allow LAN access
-	if err := fiberApp.Listen("0.0.0.0:3000"); err != nil {
-		log.Fatal(err)
-	}
+	// Listen on all interfaces (0.0.0.0) by default to allow LAN access. A port
+	// clash falls back to the next free port rather than stopping the app.
+	address, port := app.serverAddress()
+	ln, activePort, err := listenWithFallback(address, port)
+	if err != nil {
+		return fmt.Errorf("failed to start web server: %v", err)
+	}
+	app.mu.Lock()
+	app.server = fiberApp
+	app.activePort = activePort
+	https := app.httpsEnabled
+	app.mu.Unlock()
+	if https {
+		go app.startHTTPS()
+	}
+	go func() {
+		if err := fiberApp.Listener(ln); err != nil {
+			serverLog.Error("Web server stopped", "error", err)
+		}
+	}()
+	return nil
 }
 
 func main() {
+	// 'serve' runs the results server without the desktop window.
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runHeadless(os.Args[2:]); err != nil {
+			appLog.Error("Headless server failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	// 'archive' bundles a results directory into a meet archive.
+	if len(os.Args) > 1 && os.Args[1] == "archive" {
+		if err := runArchive(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	// 'site' exports the results as a static HTML site.
+	if len(os.Args) > 1 && os.Args[1] == "site" {
+		if err := runSite(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	// 'parse' prints what the parser makes of result files, without starting anything.
+	if len(os.Args) > 1 && os.Args[1] == "parse" {
+		if err := runParse(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	openLogFile()
 	app := NewApp()
-	go StartFiberServer(app)
-	go startMDNS()
+	app.loadMediaIndex()
+	app.loadAccessKeys()
+	app.loadSettings()
+	if err := StartFiberServer(app); err != nil {
+		serverLog.Error("Failed to start web server", "error", err)
+	}
+	go app.runPlaylists()
+	go app.runReplication()
+	go app.watchMDNS()
 	err := wails.Run(&options.App{
 		Title:            "PolyField - Track",
 		Width:            800,
@@ -1134,9 +1431,11 @@ func main() {
 		BackgroundColour: &options.RGBA{R: 255, G: 255, B: 255, A: 255},
 		Assets:           assets,
 		OnStartup:        app.startup,
+		OnShutdown:       func(ctx context.Context) { app.shutdown() },
 		Bind:             []interface{}{app},
 	})
 	if err != nil {
-		log.Fatal(err)
+		appLog.Error("Desktop app failed", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hashicorp/mdns"
+)
+
+const (
+	// appVersion is advertised over mDNS. Keep it in step with frontend/package.json.
+	appVersion = "3.1.0"
+	// defaultMDNSHostname is the hostname advertised when none has been configured.
+	defaultMDNSHostname = "track.local"
+	// mdnsServiceType is the DNS-SD service type the web server is advertised as.
+	mdnsServiceType = "_http._tcp"
+	// mdnsAppTag is the TXT record that marks an _http._tcp service as a PolyField instance.
+	mdnsAppTag = "app=polyfield-track"
+	// mdnsRefreshInterval is how often the registration is checked against the network interfaces.
+	mdnsRefreshInterval = 15 * time.Second
+	// discoveryTimeout is how long DiscoverInstances listens for answers.
+	discoveryTimeout = 2 * time.Second
+)
+
+// validMDNSHostname matches a single .local hostname label.
+var validMDNSHostname = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// MDNSSettings is how this instance is advertised on the LAN.
+type MDNSSettings struct {
+	InstanceName string `json:"instanceName"`
+	Hostname     string `json:"hostname"` // e.g. 'track.local'
+}
+
+// DiscoveredInstance is another PolyField instance found on the LAN.
+type DiscoveredInstance struct {
+	Name             string `json:"name"`
+	Hostname         string `json:"hostname"`
+	Address          string `json:"address"`
+	Port             int    `json:"port"`
+	URL              string `json:"url"`
+	Version          string `json:"version"`
+	Meet             string `json:"meet"`
+	APIPath          string `json:"apiPath"`
+	HostnameConflict bool   `json:"hostnameConflict"` // Advertises the same hostname as this instance
+}
+
+// defaultInstanceName includes the computer name so two laptops at a venue don't collide.
+func defaultInstanceName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "PolyField Track"
+	}
+	host = strings.TrimSuffix(host, ".local")
+	return fmt.Sprintf("PolyField Track (%s)", host)
+}
+
+// normaliseMDNSHostname lowercases a hostname and adds the .local domain.
+func normaliseMDNSHostname(hostname string) (string, error) {
+	hostname = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+	label := strings.TrimSuffix(hostname, ".local")
+	if !validMDNSHostname.MatchString(label) {
+		return "", fmt.Errorf("invalid hostname %q, use letters, numbers and hyphens, e.g. track-2.local", hostname)
+	}
+	return label + ".local", nil
+}
+
+// GetMDNSSettings returns the instance name and hostname advertised over mDNS.
+func (a *App) GetMDNSSettings() MDNSSettings {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	settings := MDNSSettings{InstanceName: a.instanceName, Hostname: a.mdnsHostname}
+	if settings.InstanceName == "" {
+		settings.InstanceName = defaultInstanceName()
+	}
+	if settings.Hostname == "" {
+		settings.Hostname = defaultMDNSHostname
+	}
+	return settings
+}
+
+// SetMDNSSettings changes the advertised instance name and hostname and re-registers.
+// Empty values restore the defaults.
+func (a *App) SetMDNSSettings(instanceName string, hostname string) error {
+	instanceName = strings.TrimSpace(instanceName)
+	if len(instanceName) > 63 {
+		return fmt.Errorf("instance name must be at most 63 characters")
+	}
+	if hostname != "" {
+		normalised, err := normaliseMDNSHostname(hostname)
+		if err != nil {
+			return err
+		}
+		hostname = normalised
+	}
+	a.mu.Lock()
+	a.instanceName = instanceName
+	a.mdnsHostname = hostname
+	a.mu.Unlock()
+	a.scheduleSave()
+	a.stopMDNS()
+	a.startMDNS()
+	// Reissue the HTTPS certificate for the new hostname.
+	if a.GetHTTPSEnabled() {
+		a.stopHTTPS()
+		a.startHTTPS()
+	}
+	return nil
+}
+
+// mdnsService builds the mDNS registration for the current settings, port, meet and
+// network interfaces. It returns nil if there is nothing to advertise yet.
+func (a *App) mdnsService() (*mdns.MDNSService, error) {
+	settings := a.GetMDNSSettings()
+	meet := a.GetMeet()
+	a.mu.Lock()
+	port, httpsPort, dir := a.activePort, a.activeHTTPSPort, a.monitoredDir
+	if a.replica.active && a.replica.hostname != "" {
+		// A backup that has taken over answers for the primary's hostname.
+		settings.Hostname = a.replica.hostname
+	}
+	a.mu.Unlock()
+	ips := getLANIPs()
+	if port == 0 || len(ips) == 0 {
+		return nil, nil
+	}
+	txt := []string{
+		mdnsAppTag,
+		"id=" + a.instanceID,
+		"version=" + appVersion,
+		"path=/",
+		"api=/",
+	}
+	if name := advertisedMeet(meet.Name, dir); name != "" {
+		txt = append(txt, "meet="+name)
+	}
+	if httpsPort != 0 {
+		txt = append(txt, "https="+strconv.Itoa(httpsPort))
+	}
+	return mdns.NewMDNSService(settings.InstanceName, mdnsServiceType, "", settings.Hostname+".", port, ips, txt)
+}
+
+// advertisedMeet returns the meet name advertised over mDNS: the name from the meet
+// details, or the results folder's name if the meet hasn't been named.
+func advertisedMeet(meetName string, dir string) string {
+	if name := strings.TrimSpace(meetName); name != "" {
+		return name
+	}
+	if dir != "" {
+		return filepath.Base(dir)
+	}
+	return ""
+}
+
+// mdnsServiceKey identifies a registration, so it is only replaced when something changed.
+func mdnsServiceKey(service *mdns.MDNSService) string {
+	if service == nil {
+		return ""
+	}
+	ips := make([]string, len(service.IPs))
+	for i, ip := range service.IPs {
+		ips[i] = ip.String()
+	}
+	sort.Strings(ips)
+	return fmt.Sprintf("%s|%s|%d|%v|%v", service.Instance, service.HostName, service.Port, ips, service.TXT)
+}
+
+// startMDNS registers the web server via mDNS so LAN devices can reach it at the
+// configured hostname. It does nothing if the registration hasn't changed.
+func (a *App) startMDNS() {
+	a.mdnsMu.Lock()
+	defer a.mdnsMu.Unlock()
+	service, err := a.mdnsService()
+	if err != nil {
+		mdnsLog.Error("Failed to create service", "error", err)
+		return
+	}
+	key := mdnsServiceKey(service)
+	a.mu.Lock()
+	unchanged := key == a.mdnsKey
+	a.mu.Unlock()
+	if unchanged {
+		return
+	}
+	a.stopMDNS()
+	if service == nil {
+		mdnsLog.Warn("No LAN IP addresses found, skipping registration")
+		a.mu.Lock()
+		a.mdnsKey = key
+		a.mu.Unlock()
+		return
+	}
+	server, err := mdns.NewServer(&mdns.Config{Zone: service})
+	if err != nil {
+		mdnsLog.Error("Failed to start server", "error", err)
+		return
+	}
+	a.mu.Lock()
+	a.mdnsServer = server
+	a.mdnsKey = key
+	a.mu.Unlock()
+	mdnsLog.Info("Registered", "instance", service.Instance, "hostname", service.HostName, "port", service.Port, "ips", service.IPs)
+}
+
+// stopMDNS withdraws the mDNS registration, e.g. before the port changes.
+func (a *App) stopMDNS() {
+	a.mu.Lock()
+	server := a.mdnsServer
+	a.mdnsServer = nil
+	a.mdnsKey = ""
+	a.mu.Unlock()
+	if server != nil {
+		server.Shutdown()
+	}
+}
+
+// watchMDNS keeps the mDNS registration up to date as network interfaces come and
+// go (e.g. the laptop joins the venue Wi-Fi after starting) and the meet changes.
+func (a *App) watchMDNS() {
+	a.startMDNS()
+	ticker := time.NewTicker(mdnsRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a.startMDNS()
+	}
+}
+
+// txtValue returns the value of key in a list of 'key=value' TXT records.
+func txtValue(fields []string, key string) string {
+	for _, field := range fields {
+		if k, v, ok := strings.Cut(field, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// unescapeInstanceName removes the DNS escaping (e.g. 'PolyField\ Track') from an instance name.
+func unescapeInstanceName(name string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range name {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// DiscoverInstances lists the other PolyField instances advertising on the LAN.
+func (a *App) DiscoverInstances() ([]DiscoveredInstance, error) {
+	own := a.GetMDNSSettings()
+	entries := make(chan *mdns.ServiceEntry, 32)
+	found := make(map[string]DiscoveredInstance)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for entry := range entries {
+			isPolyField := false
+			for _, field := range entry.InfoFields {
+				if field == mdnsAppTag {
+					isPolyField = true
+				}
+			}
+			if !isPolyField || txtValue(entry.InfoFields, "id") == a.instanceID || entry.AddrV4 == nil {
+				continue
+			}
+			hostname := strings.TrimSuffix(entry.Host, ".")
+			found[entry.Name] = DiscoveredInstance{
+				Name:             unescapeInstanceName(strings.TrimSuffix(entry.Name, "."+mdnsServiceType+".local.")),
+				Hostname:         hostname,
+				Address:          entry.AddrV4.String(),
+				Port:             entry.Port,
+				URL:              "http://" + net.JoinHostPort(entry.AddrV4.String(), strconv.Itoa(entry.Port)),
+				Version:          txtValue(entry.InfoFields, "version"),
+				Meet:             txtValue(entry.InfoFields, "meet"),
+				APIPath:          txtValue(entry.InfoFields, "api"),
+				HostnameConflict: hostname == own.Hostname,
+			}
+		}
+	}()
+	params := mdns.DefaultParams(mdnsServiceType)
+	params.Entries = entries
+	params.Timeout = discoveryTimeout
+	params.DisableIPv6 = true
+	params.Logger = log.New(io.Discard, "", 0)
+	err := mdns.Query(params)
+	close(entries)
+	<-done
+	if err != nil {
+		return nil, fmt.Errorf("mDNS discovery failed: %v", err)
+	}
+
+	result := make([]DiscoveredInstance, 0, len(found))
+	for _, instance := range found {
+		result = append(result, instance)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// registerDiscoveryRoutes adds the LAN discovery endpoint to the Fiber server.
+func registerDiscoveryRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/instances", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		instances, err := app.DiscoverInstances()
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(instances)
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestAdvertisedMeet(t *testing.T) {
+	tests := []struct {
+		meetName string
+		dir      string
+		want     string
+	}{
+		{"County Championships", "/results/2026-county", "County Championships"},
+		{"  Summer Open ", "/results/open", "Summer Open"},
+		{"", "/results/2026-county", "2026-county"},
+		{"   ", "/results/2026-county", "2026-county"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := advertisedMeet(tt.meetName, tt.dir); got != tt.want {
+			t.Errorf("advertisedMeet(%q, %q) = %q, want %q", tt.meetName, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxMediaSize is the largest image or video accepted by the media library.
+const maxMediaSize = 200 * 1024 * 1024
+
+// mediaExtensions maps the content types accepted by the media library to file extensions.
+var mediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+// MediaItem is an image or video in the media library. The ID is derived from
+// the file contents, so the same file uploaded twice is only stored once and a
+// URL for an ID never changes.
+type MediaItem struct {
+	ID          string `json:"id"` // Content hash plus extension, e.g. '3f9a2c0d1e4b5a6c.png'
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Uploaded    int64  `json:"uploaded"` // Unix time
+}
+
+// mediaDir returns the directory the media library is stored in.
+func mediaDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "PolyField-Track", "media"), nil
+}
+
+// loadMediaIndex reads the media library index. A missing index is an empty library.
+func (a *App) loadMediaIndex() {
+	dir, err := mediaDir()
+	if err != nil {
+		mediaLog.Error("Failed to locate media library", "error", err)
+		return
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			mediaLog.Error("Failed to load media index", "error", err)
+		}
+		return
+	}
+	var items []MediaItem
+	if err := json.Unmarshal(raw, &items); err != nil {
+		mediaLog.Error("Failed to read media index", "error", err)
+		return
+	}
+	a.mediaMu.Lock()
+	defer a.mediaMu.Unlock()
+	for i := range items {
+		item := items[i]
+		a.media[item.ID] = &item
+	}
+	mediaLog.Info("Loaded media library", "count", len(items))
+}
+
+// saveMediaIndexLocked writes the media library index. The caller must hold a.mediaMu.
+func (a *App) saveMediaIndexLocked(dir string) error {
+	items := make([]MediaItem, 0, len(a.media))
+	for _, item := range a.media {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Uploaded < items[j].Uploaded })
+	raw, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode media index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), raw, 0644); err != nil {
+		return fmt.Errorf("failed to save media index: %v", err)
+	}
+	return nil
+}
+
+// addMedia stores a file in the media library and returns its entry. The file is
+// copied to disk as it is read, so large videos are never held in memory.
+func (a *App) addMedia(name string, r io.Reader) (*MediaItem, error) {
+	dir, err := mediaDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to save media: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, maxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save media: %v", err)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if size > maxMediaSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxMediaSize/(1024*1024))
+	}
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to save media: %v", err)
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := mediaExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type %s", contentType)
+	}
+	id := hex.EncodeToString(hash.Sum(nil)[:8]) + ext
+
+	a.mediaMu.Lock()
+	defer a.mediaMu.Unlock()
+	if item, exists := a.media[id]; exists {
+		return item, nil
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to save media: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, id)); err != nil {
+		return nil, fmt.Errorf("failed to save media: %v", err)
+	}
+	item := &MediaItem{
+		ID:          id,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		Uploaded:    time.Now().Unix(),
+	}
+	a.media[id] = item
+	if err := a.saveMediaIndexLocked(dir); err != nil {
+		return nil, err
+	}
+	mediaLog.Info("Media added", "id", id, "name", name, "bytes", size)
+	return item, nil
+}
+
+// mediaPath returns the file path of a media item, or an error if it doesn't exist.
+func (a *App) mediaPath(id string) (string, *MediaItem, error) {
+	a.mediaMu.Lock()
+	item, ok := a.media[id]
+	a.mediaMu.Unlock()
+	if !ok {
+		return "", nil, fmt.Errorf("no media with id %s", id)
+	}
+	dir, err := mediaDir()
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.Join(dir, item.ID), item, nil
+}
+
+// decodeBase64Data decodes base64 data, with or without a data URL prefix.
+func decodeBase64Data(value string) ([]byte, error) {
+	if idx := strings.Index(value, ","); idx != -1 && strings.HasPrefix(value, "data:") {
+		value = value[idx+1:]
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// GetMedia returns the media library, oldest first.
+func (a *App) GetMedia() []MediaItem {
+	a.mediaMu.Lock()
+	defer a.mediaMu.Unlock()
+	items := make([]MediaItem, 0, len(a.media))
+	for _, item := range a.media {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Uploaded < items[j].Uploaded })
+	return items
+}
+
+// UploadMedia adds a base64 encoded image or video to the media library (called from frontend).
+func (a *App) UploadMedia(name string, base64Data string) (*MediaItem, error) {
+	data, err := decodeBase64Data(base64Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode media data: %v", err)
+	}
+	return a.addMedia(name, bytes.NewReader(data))
+}
+
+// DeleteMedia removes an item from the media library and from any channel showing
+// it. Media used by a playlist can't be deleted until it is removed from the playlist.
+func (a *App) DeleteMedia(id string) error {
+	path, _, err := a.mediaPath(id)
+	if err != nil {
+		return err
+	}
+	if playlist := a.playlistUsingMedia(id); playlist != "" {
+		return fmt.Errorf("media %s is used by playlist %q", id, playlist)
+	}
+	dir := filepath.Dir(path)
+	a.mediaMu.Lock()
+	delete(a.media, id)
+	err = a.saveMediaIndexLocked(dir)
+	a.mediaMu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete media: %v", err)
+	}
+
+	a.mu.Lock()
+	for _, state := range a.channels {
+		if state.ImageID == id {
+			state.ImageID = ""
+		}
+		slideshow := state.SlideshowIDs[:0:0]
+		for _, slide := range state.SlideshowIDs {
+			if slide != id {
+				slideshow = append(slideshow, slide)
+			}
+		}
+		state.SlideshowIDs = slideshow
+	}
+	a.mu.Unlock()
+	a.scheduleSave()
+	mediaLog.Info("Media deleted", "id", id)
+	return nil
+}
+
+// playlistUsingMedia returns the name of a playlist showing a media item, if any.
+func (a *App) playlistUsingMedia(id string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, playlist := range a.playlists {
+		for _, item := range playlist.Items {
+			if item.Type == "image" && item.MediaID == id {
+				return playlist.Name
+			}
+		}
+	}
+	return ""
+}
+
+// limitBodies is Fiber middleware that applies the default body limit to every
+// route except media uploads. The server streams bodies over the limit instead of
+// rejecting them, so this runs before anything reads the body.
+func limitBodies(c *fiber.Ctx) error {
+	limit := fiber.DefaultBodyLimit
+	if c.Method() == fiber.MethodPost && c.Path() == "/media" {
+		// Leave room for the multipart headers around the file.
+		limit = maxMediaSize + 1024*1024
+	}
+	length := c.Request().Header.ContentLength()
+	if length == -1 || length > limit {
+		// The unread body is still on the connection, so don't keep it alive.
+		c.Context().SetConnectionClose()
+	}
+	if length == -1 {
+		// Chunked bodies have no length to check up front.
+		return c.Status(fiber.StatusLengthRequired).JSON(map[string]interface{}{"error": "Content-Length required"})
+	}
+	if length > limit {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(map[string]interface{}{"error": "request body too large"})
+	}
+	return c.Next()
+}
+
+// SetChannelSlideshow sets the images a channel's screensaver rotates through.
+// interval is the number of seconds each image is shown for.
+func (a *App) SetChannelSlideshow(channel string, ids []string, interval int) error {
+	return a.setChannelSlideshow(sourceDesktop, channel, ids, interval)
+}
+
+// setChannelSlideshow is SetChannelSlideshow, audited against source.
+func (a *App) setChannelSlideshow(source string, channel string, ids []string, interval int) error {
+	for _, id := range ids {
+		if _, _, err := a.mediaPath(id); err != nil {
+			return err
+		}
+	}
+	if interval <= 0 {
+		interval = 10
+	}
+	return a.updateChannel(source, channel, func(state *DisplayState) {
+		state.SlideshowIDs = ids
+		state.SlideshowInterval = interval
+	})
+}
+
+// resolveDisplayImage turns the image in a display state update into a media
+// library reference: base64 images are added to the library and /media/ URLs are
+// reduced to their ID. It returns false if the image couldn't be resolved (e.g. a
+// browser-local blob URL), in which case the channel keeps its current image.
+func (a *App) resolveDisplayImage(update *DisplayState) bool {
+	value := strings.TrimSpace(update.ImageBase64)
+	update.ImageBase64 = ""
+	if update.ImageID != "" || value == "" {
+		return true
+	}
+	if idx := strings.LastIndex(value, "/media/"); idx != -1 {
+		update.ImageID = value[idx+len("/media/"):]
+		return true
+	}
+	data, err := decodeBase64Data(value)
+	if err != nil {
+		return false
+	}
+	item, err := a.addMedia("screensaver", bytes.NewReader(data))
+	if err != nil {
+		mediaLog.Error("Failed to add screensaver image to media library", "error", err)
+		return false
+	}
+	update.ImageID = item.ID
+	return true
+}
+
+// migrateChannelImages moves base64 screensaver images saved by older versions into the media library.
+func (a *App) migrateChannelImages() {
+	a.mu.Lock()
+	legacy := make(map[string]string)
+	for name, state := range a.channels {
+		if state.ImageBase64 != "" {
+			legacy[name] = state.ImageBase64
+		}
+	}
+	a.mu.Unlock()
+	for name, image := range legacy {
+		update := DisplayState{ImageBase64: image}
+		keep := a.resolveDisplayImage(&update)
+		a.updateChannel(sourceApp, name, func(state *DisplayState) {
+			state.ImageBase64 = ""
+			if keep {
+				state.ImageID = update.ImageID
+			}
+		})
+	}
+}
+
+// registerMediaRoutes adds the media library endpoints to the Fiber server.
+func registerMediaRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/media", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetMedia())
+	})
+	fiberApp.Get("/media/:id", func(c *fiber.Ctx) error {
+		if file, item, ok := app.archivedMedia(c.Params("id")); ok {
+			reader, err := file.Open()
+			if err != nil {
+				return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+			}
+			c.Set("Content-Type", item.ContentType)
+			return c.SendStream(reader, int(file.UncompressedSize64))
+		}
+		path, item, err := app.mediaPath(c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		// IDs are content hashes, so a URL always refers to the same bytes.
+		etag := `"` + item.ID + `"`
+		c.Set("Cache-Control", "public, max-age=31536000, immutable")
+		c.Set("ETag", etag)
+		if c.Get("If-None-Match") == etag {
+			return c.SendStatus(304)
+		}
+		c.Set("Content-Type", item.ContentType)
+		return c.SendFile(path)
+	})
+	fiberApp.Post("/media", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		file, err := header.Open()
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		defer file.Close()
+		item, err := app.addMedia(header.Filename, file)
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(item)
+	})
+	fiberApp.Delete("/media/:id", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.DeleteMedia(c.Params("id")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/channels/:name/slideshow", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var req struct {
+			IDs      []string `json:"ids"`
+			Interval int      `json:"interval"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.setChannelSlideshow(app.requestSource(c), c.Params("name"), req.IDs, req.Interval); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLimitBodies(t *testing.T) {
+	fiberApp := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	fiberApp.Use(limitBodies)
+	ok := func(c *fiber.Ctx) error {
+		c.Body()
+		return c.SendStatus(200)
+	}
+	fiberApp.Post("/auth/login", ok)
+	fiberApp.Post("/media", ok)
+
+	tests := []struct {
+		name   string
+		path   string
+		length int
+		want   int
+	}{
+		{"small login", "/auth/login", 100, 200},
+		{"oversized login", "/auth/login", fiber.DefaultBodyLimit + 1, 413},
+		{"large upload", "/media", 50 * 1024 * 1024, 200},
+		{"oversized upload", "/media", maxMediaSize + 2*1024*1024, 413},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, bytes.NewReader(make([]byte, tt.length)))
+			resp, err := fiberApp.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+// pngHeader is enough of a PNG file for content type detection.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestMediaUploadAndDelete(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	app := NewApp()
+	app.settingsFile = t.TempDir() + "/settings.json"
+	fiberApp := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	fiberApp.Use(limitBodies)
+	registerMediaRoutes(fiberApp, app)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "sponsor.png")
+	part.Write(append(pngHeader, bytes.Repeat([]byte{0}, 6*1024*1024)...))
+	form.Close()
+	req := httptest.NewRequest("POST", "/media", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("X-Operator-Token", app.GetOperatorToken())
+	resp, err := fiberApp.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("upload status = %d, want 200", resp.StatusCode)
+	}
+	media := app.GetMedia()
+	if len(media) != 1 || media[0].ContentType != "image/png" || media[0].Size != int64(len(pngHeader)+6*1024*1024) {
+		t.Fatalf("media after upload = %+v", media)
+	}
+	id := media[0].ID
+
+	if err := app.SavePlaylist(Playlist{Name: "Sponsors", Items: []PlaylistItem{{Type: "image", MediaID: id, Duration: 10}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SavePlaylist(Playlist{Name: "Missing", Items: []PlaylistItem{{Type: "image", MediaID: "nope.png", Duration: 10}}}); err == nil {
+		t.Error("SavePlaylist() with unknown media succeeded")
+	}
+	if err := app.DeleteMedia(id); err == nil || !strings.Contains(err.Error(), "Sponsors") {
+		t.Errorf("DeleteMedia() of media in a playlist = %v, want an error naming the playlist", err)
+	}
+	if err := app.DeletePlaylist("Sponsors"); err != nil {
+		t.Fatal(err)
+	}
+	app.channels[defaultChannel].ImageID = id
+	app.channels[defaultChannel].SlideshowIDs = []string{id, "other.png"}
+	if err := app.DeleteMedia(id); err != nil {
+		t.Fatal(err)
+	}
+	state := app.GetDisplayState()
+	if state.ImageID != "" || len(state.SlideshowIDs) != 1 {
+		t.Errorf("channel after delete shows image %q and slideshow %v", state.ImageID, state.SlideshowIDs)
+	}
+}
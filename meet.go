@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Session filters for the results, see filterResults. Any other value is a session ID.
+const (
+	sessionAll     = "all"
+	sessionToday   = "today"
+	sessionCurrent = "current"
+)
+
+const (
+	meetDateLayout = "2006-01-02"
+	meetTimeLayout = "15:04"
+)
+
+// Meet describes the competition in the monitored directory. A multi-day meet
+// keeps every day's results in one folder, and sessions split them up again.
+type Meet struct {
+	Name          string        `json:"name"`
+	Venue         string        `json:"venue"`
+	StartDate     string        `json:"startDate"`     // YYYY-MM-DD
+	EndDate       string        `json:"endDate"`       // YYYY-MM-DD
+	Sessions      []MeetSession `json:"sessions"`      // Schedule; when empty each day is a session, split at SessionBreaks
+	SessionBreaks []string      `json:"sessionBreaks"` // Times of day (HH:MM) that start a new session when there is no schedule
+	DisplayFilter string        `json:"displayFilter"` // What displays show by default: 'all', 'today' or 'current'
+}
+
+// MeetSession is a session in the meet schedule. It runs until the next session starts.
+type MeetSession struct {
+	Name  string `json:"name"`
+	Date  string `json:"date"`  // YYYY-MM-DD
+	Start string `json:"start"` // HH:MM
+}
+
+// Session is a session of the meet with the results saved during it.
+type Session struct {
+	ID      string `json:"id"` // Start date and time, e.g. '2026-06-13T09:00'
+	Name    string `json:"name"`
+	Start   int64  `json:"start"` // Unix time
+	End     int64  `json:"end"`   // Unix time the next session starts, 0 for the last session
+	Results int    `json:"results"`
+	Current bool   `json:"current"` // The session running now
+}
+
+// validate checks the dates and times in a meet.
+func (m *Meet) validate() error {
+	for _, date := range []string{m.StartDate, m.EndDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.ParseInLocation(meetDateLayout, date, time.Local); err != nil {
+			return fmt.Errorf("invalid date %q, use YYYY-MM-DD", date)
+		}
+	}
+	if m.StartDate != "" && m.EndDate != "" && m.EndDate < m.StartDate {
+		return fmt.Errorf("end date is before start date")
+	}
+	for _, session := range m.Sessions {
+		if _, err := time.ParseInLocation(meetDateLayout+" "+meetTimeLayout, session.Date+" "+session.Start, time.Local); err != nil {
+			return fmt.Errorf("invalid start %q %q for session %q, use YYYY-MM-DD and HH:MM", session.Date, session.Start, session.Name)
+		}
+	}
+	for _, sessionBreak := range m.SessionBreaks {
+		if _, err := time.Parse(meetTimeLayout, sessionBreak); err != nil {
+			return fmt.Errorf("invalid session break %q, use HH:MM", sessionBreak)
+		}
+	}
+	switch m.DisplayFilter {
+	case "", sessionAll, sessionToday, sessionCurrent:
+	default:
+		return fmt.Errorf("unknown display filter %q, use all, today or current", m.DisplayFilter)
+	}
+	return nil
+}
+
+// sessionStarts returns the meet's sessions, oldest first, without result counts.
+// With no schedule, every day from the start date to the end date, every day with
+// a result and today is a session, split at the session breaks.
+func (m *Meet) sessionStarts(results []*LifData, now time.Time) []Session {
+	var sessions []Session
+	if len(m.Sessions) > 0 {
+		for _, scheduled := range m.Sessions {
+			start, err := time.ParseInLocation(meetDateLayout+" "+meetTimeLayout, scheduled.Date+" "+scheduled.Start, time.Local)
+			if err != nil {
+				continue
+			}
+			name := scheduled.Name
+			if name == "" {
+				name = start.Format("Mon 2 Jan 15:04")
+			}
+			sessions = append(sessions, Session{ID: start.Format("2006-01-02T15:04"), Name: name, Start: start.Unix()})
+		}
+	} else {
+		days := map[string]bool{now.Format(meetDateLayout): true}
+		for _, data := range results {
+			days[time.Unix(data.ModifiedTime, 0).Format(meetDateLayout)] = true
+		}
+		if first, err := time.ParseInLocation(meetDateLayout, m.StartDate, time.Local); err == nil {
+			last, err := time.ParseInLocation(meetDateLayout, m.EndDate, time.Local)
+			if err != nil {
+				last = first
+			}
+			for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+				days[day.Format(meetDateLayout)] = true
+			}
+		}
+		breaks := append([]string{"00:00"}, m.SessionBreaks...)
+		sort.Strings(breaks)
+		for day := range days {
+			for i, sessionBreak := range breaks {
+				start, err := time.ParseInLocation(meetDateLayout+" "+meetTimeLayout, day+" "+sessionBreak, time.Local)
+				if err != nil || (i > 0 && sessionBreak == breaks[i-1]) {
+					continue
+				}
+				name := start.Format("Mon 2 Jan")
+				if i > 0 {
+					name += " from " + sessionBreak
+				}
+				sessions = append(sessions, Session{ID: start.Format("2006-01-02T15:04"), Name: name, Start: start.Unix()})
+			}
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Start < sessions[j].Start })
+	for i := range sessions {
+		if i+1 < len(sessions) {
+			sessions[i].End = sessions[i+1].Start
+		}
+	}
+	return sessions
+}
+
+// sessionIndex returns the session a time falls in: the last one started by then,
+// or the first session for anything earlier.
+func sessionIndex(sessions []Session, t int64) int {
+	index := sort.Search(len(sessions), func(i int) bool { return sessions[i].Start > t }) - 1
+	if index < 0 {
+		return 0
+	}
+	return index
+}
+
+// sessions returns the meet's sessions with the number of results saved in each,
+// marking the session running now.
+func (m *Meet) sessions(results []*LifData, now time.Time) []Session {
+	sessions := m.sessionStarts(results, now)
+	if len(sessions) == 0 {
+		return []Session{}
+	}
+	for _, data := range results {
+		sessions[sessionIndex(sessions, data.ModifiedTime)].Results++
+	}
+	sessions[sessionIndex(sessions, now.Unix())].Current = true
+	return sessions
+}
+
+// notFoundError reports a session or result file that doesn't exist, which the
+// REST API answers with 404 rather than 500.
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+
+// filterResults returns the results saved during a session: 'all', 'today',
+// 'current' for the session running now, or a session ID.
+func (m *Meet) filterResults(results []*LifData, filter string, now time.Time) ([]*LifData, error) {
+	if filter == "" || filter == sessionAll {
+		return results, nil
+	}
+	filtered := []*LifData{}
+	if filter == sessionToday {
+		today := now.Format(meetDateLayout)
+		for _, data := range results {
+			if time.Unix(data.ModifiedTime, 0).Format(meetDateLayout) == today {
+				filtered = append(filtered, data)
+			}
+		}
+		return filtered, nil
+	}
+	sessions := m.sessionStarts(results, now)
+	if len(sessions) == 0 {
+		return filtered, nil
+	}
+	index := sessionIndex(sessions, now.Unix())
+	if filter != sessionCurrent {
+		index = -1
+		for i, session := range sessions {
+			if session.ID == filter {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, notFoundError(fmt.Sprintf("no session %q", filter))
+		}
+	}
+	for _, data := range results {
+		if sessionIndex(sessions, data.ModifiedTime) == index {
+			filtered = append(filtered, data)
+		}
+	}
+	return filtered, nil
+}
+
+// GetMeet returns the meet details for the monitored directory.
+func (a *App) GetMeet() Meet {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive != nil {
+		// An archived meet is over, so there is no today or current session to show.
+		meet := a.archive.manifest.Meet
+		meet.DisplayFilter = sessionAll
+		return meet
+	}
+	if a.meet == nil {
+		return Meet{Sessions: []MeetSession{}, SessionBreaks: []string{}, DisplayFilter: sessionAll}
+	}
+	meet := *a.meet
+	if meet.DisplayFilter == "" {
+		meet.DisplayFilter = sessionAll
+	}
+	return meet
+}
+
+// SetMeet changes the meet details. They are saved with the settings, and in the
+// per-meet settings file if the results directory has one.
+func (a *App) SetMeet(meet Meet) error {
+	meet.Name = strings.TrimSpace(meet.Name)
+	meet.Venue = strings.TrimSpace(meet.Venue)
+	if err := a.archiveReadOnly(); err != nil {
+		return err
+	}
+	if err := meet.validate(); err != nil {
+		return err
+	}
+	if meet.Sessions == nil {
+		meet.Sessions = []MeetSession{}
+	}
+	if meet.SessionBreaks == nil {
+		meet.SessionBreaks = []string{}
+	}
+	a.mu.Lock()
+	a.meet = &meet
+	a.mu.Unlock()
+	settingsLog.Info("Meet updated", "name", meet.Name, "sessions", len(meet.Sessions))
+	a.scheduleSave()
+	// Advertise the new meet name.
+	go a.startMDNS()
+	return nil
+}
+
+// GetSessions returns the sessions of the meet, oldest first, with the number of
+// results saved in each.
+func (a *App) GetSessions() ([]Session, error) {
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, err
+	}
+	meet := a.GetMeet()
+	return meet.sessions(results, time.Now()), nil
+}
+
+// GetSessionResults returns the results saved during a session: 'all', 'today',
+// 'current', a session ID, or empty for the meet's display filter.
+func (a *App) GetSessionResults(filter string) ([]*LifData, error) {
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, err
+	}
+	meet := a.GetMeet()
+	if filter == "" {
+		filter = meet.DisplayFilter
+	}
+	return meet.filterResults(results, filter, time.Now())
+}
+
+// registerMeetRoutes adds the meet and session endpoints to the Fiber server.
+func registerMeetRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/meet", func(c *fiber.Ctx) error {
+		return c.JSON(app.GetMeet())
+	})
+	fiberApp.Post("/meet", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var meet Meet
+		if err := c.BodyParser(&meet); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetMeet(meet); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Get("/sessions", func(c *fiber.Ctx) error {
+		sessions, err := app.GetSessions()
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(sessions)
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterResults(t *testing.T) {
+	at := func(value string) int64 {
+		t.Helper()
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.Unix()
+	}
+	results := []*LifData{
+		{FileName: "sat-morning.lif", ModifiedTime: at("2026-06-13 10:15")},
+		{FileName: "sat-afternoon.lif", ModifiedTime: at("2026-06-13 14:30")},
+		{FileName: "sun-early.lif", ModifiedTime: at("2026-06-14 08:50")},
+		{FileName: "sun-morning.lif", ModifiedTime: at("2026-06-14 09:30")},
+	}
+	now := time.Unix(at("2026-06-14 11:00"), 0)
+	scheduled := Meet{Sessions: []MeetSession{
+		{Name: "Saturday", Date: "2026-06-13", Start: "09:00"},
+		{Name: "Sunday", Date: "2026-06-14", Start: "09:00"},
+	}}
+	byBreak := Meet{SessionBreaks: []string{"13:00"}}
+
+	tests := []struct {
+		name    string
+		meet    Meet
+		filter  string
+		want    []string
+		wantErr bool
+	}{
+		{"no filter", scheduled, "", []string{"sat-morning.lif", "sat-afternoon.lif", "sun-early.lif", "sun-morning.lif"}, false},
+		{"all", scheduled, sessionAll, []string{"sat-morning.lif", "sat-afternoon.lif", "sun-early.lif", "sun-morning.lif"}, false},
+		{"today", scheduled, sessionToday, []string{"sun-early.lif", "sun-morning.lif"}, false},
+		{"current scheduled session", scheduled, sessionCurrent, []string{"sun-morning.lif"}, false},
+		{"scheduled session by id", scheduled, "2026-06-13T09:00", []string{"sat-morning.lif", "sat-afternoon.lif", "sun-early.lif"}, false},
+		{"session break", byBreak, "2026-06-13T13:00", []string{"sat-afternoon.lif"}, false},
+		{"current day without schedule", byBreak, sessionCurrent, []string{"sun-early.lif", "sun-morning.lif"}, false},
+		{"unknown session", scheduled, "2026-06-15T09:00", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := tt.meet.filterResults(results, tt.filter, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, data := range filtered {
+				got = append(got, data.FileName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterResults() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
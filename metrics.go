@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// serverMetrics counts what the server has done since it started, for /metrics.
+type serverMetrics struct {
+	mu             sync.Mutex
+	started        time.Time
+	parseSuccesses int64
+	parseFailures  int64
+	parseSeconds   float64 // total time spent parsing
+	cacheHits      int64
+	cacheMisses    int64
+	watcherErrors  int64
+	lastResult     time.Time // when the newest result was saved, zero if none yet
+	requests       map[requestKey]int64
+}
+
+// requestKey identifies a request counter. route is the route pattern, not the
+// path requested, so that IDs in paths don't create a counter each.
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+// cachedParse is a parsed result file, valid while the file's contents are unchanged.
+type cachedParse struct {
+	hash string   // hex SHA-256 of the contents parsed
+	data *LifData // before overrides are applied, nil if parsing failed
+	err  error
+}
+
+// recordParse counts a parse of a result file and how long it took.
+func (m *serverMetrics) recordParse(elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parseSeconds += elapsed.Seconds()
+	if err != nil {
+		m.parseFailures++
+	} else {
+		m.parseSuccesses++
+	}
+}
+
+// recordCache counts a parse cache lookup.
+func (m *serverMetrics) recordCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// recordWatcherError counts an error reported by the directory watcher.
+func (m *serverMetrics) recordWatcherError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watcherErrors++
+}
+
+// recordResult notes that a result was saved at t, keeping the newest.
+func (m *serverMetrics) recordResult(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.After(m.lastResult) {
+		m.lastResult = t
+	}
+}
+
+// recordRequest counts a request handled by the web server.
+func (m *serverMetrics) recordRequest(method string, route string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.requests == nil {
+		m.requests = make(map[requestKey]int64)
+	}
+	m.requests[requestKey{method, route, status}]++
+}
+
+// parseResultCached parses a result file, reusing the previous parse if the file's
+// contents haven't changed. Overrides are applied by the caller. Most calls come
+// from /all-lif, which web displays poll and which would otherwise re-parse every
+// file in the directory each time.
+func (a *App) parseResultCached(path string) (*LifData, error) {
+	hash, err := fileHash(path)
+	if err != nil {
+		return nil, err
+	}
+	a.parseCacheMu.Lock()
+	cached, ok := a.parseCache[path]
+	a.parseCacheMu.Unlock()
+	if ok && cached.hash == hash {
+		a.metrics.recordCache(true)
+		if cached.err != nil {
+			return nil, cached.err
+		}
+		return copyLifData(cached.data), nil
+	}
+	a.metrics.recordCache(false)
+
+	start := time.Now()
+	data, err := parseFile(path)
+	a.metrics.recordParse(time.Since(start), err)
+	// Only cache the parse if the file wasn't saved again while it was read.
+	if after, hashErr := fileHash(path); hashErr == nil && after == hash {
+		a.parseCacheMu.Lock()
+		a.parseCache[path] = cachedParse{hash: hash, data: data, err: err}
+		a.parseCacheMu.Unlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return copyLifData(data), nil
+}
+
+// copyLifData returns a copy of data whose competitors can be changed, e.g. by
+// overrides, without changing data.
+func copyLifData(data *LifData) *LifData {
+	copied := *data
+	copied.Competitors = append([]Competitor(nil), data.Competitors...)
+	return &copied
+}
+
+// forgetParse drops the cached parse of a result file that was removed or renamed.
+func (a *App) forgetParse(path string) {
+	a.parseCacheMu.Lock()
+	delete(a.parseCache, path)
+	a.parseCacheMu.Unlock()
+}
+
+// clearParseCache forgets every cached parse, e.g. when another directory is opened.
+func (a *App) clearParseCache() {
+	a.parseCacheMu.Lock()
+	a.parseCache = make(map[string]cachedParse)
+	a.parseCacheMu.Unlock()
+}
+
+// countResultFiles returns the number of result files in dir.
+func countResultFiles(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && isResultFile(entry.Name()) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Health is the server's health, as reported by /healthz.
+type Health struct {
+	Status                 string   `json:"status"` // 'ok' or 'degraded'
+	Problems               []string `json:"problems"`
+	UptimeSeconds          int64    `json:"uptimeSeconds"`
+	Directory              string   `json:"directory"`
+	Watching               bool     `json:"watching"`
+	FilesWatched           int      `json:"filesWatched"`
+	SecondsSinceLastResult int64    `json:"secondsSinceLastResult"` // -1 if no result has been seen
+	ReplicationRole        string   `json:"replicationRole"`
+}
+
+// GetHealth checks that the server is watching its results directory, or, on a
+// backup, that it is mirroring the primary.
+func (a *App) GetHealth() Health {
+	a.mu.Lock()
+	dir := a.monitoredDir
+	watching := a.watching != nil
+	role := a.replication.Role
+	replicaError := a.replica.lastError
+	a.mu.Unlock()
+	if role == "" {
+		role = roleStandalone
+	}
+	a.metrics.mu.Lock()
+	started, lastResult := a.metrics.started, a.metrics.lastResult
+	a.metrics.mu.Unlock()
+
+	health := Health{
+		Status:                 "ok",
+		Problems:               []string{},
+		UptimeSeconds:          int64(time.Since(started).Seconds()),
+		Directory:              dir,
+		Watching:               watching,
+		SecondsSinceLastResult: -1,
+		ReplicationRole:        role,
+	}
+	if !lastResult.IsZero() {
+		health.SecondsSinceLastResult = int64(time.Since(lastResult).Seconds())
+	}
+	if role == roleBackup {
+		// A backup serves the primary's results rather than its own directory.
+		if replicaError != "" {
+			health.Problems = append(health.Problems, "replication: "+replicaError)
+		}
+	} else if dir == "" {
+		health.Problems = append(health.Problems, "no results directory selected")
+	} else {
+		files, err := countResultFiles(dir)
+		if err != nil {
+			health.Problems = append(health.Problems, fmt.Sprintf("results directory unreadable: %v", err))
+		}
+		health.FilesWatched = files
+		if !watching {
+			health.Problems = append(health.Problems, "not watching the results directory")
+		}
+	}
+	if len(health.Problems) > 0 {
+		health.Status = "degraded"
+	}
+	return health
+}
+
+// writeMetrics writes the server's metrics in the Prometheus text format.
+func (a *App) writeMetrics(out io.Writer) {
+	health := a.GetHealth()
+	online, offline := 0, 0
+	for _, display := range a.GetDisplays() {
+		if display.Online {
+			online++
+		} else {
+			offline++
+		}
+	}
+
+	m := &a.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	boolValue := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	metric("polyfield_build_info", "gauge", "Version of the running server.")
+	fmt.Fprintf(out, "polyfield_build_info{version=%q} 1\n", appVersion)
+	metric("polyfield_uptime_seconds", "gauge", "Seconds since the server started.")
+	fmt.Fprintf(out, "polyfield_uptime_seconds %d\n", health.UptimeSeconds)
+	metric("polyfield_healthy", "gauge", "1 if /healthz reports no problems.")
+	fmt.Fprintf(out, "polyfield_healthy %d\n", boolValue(health.Status == "ok"))
+	metric("polyfield_watcher_up", "gauge", "1 if the results directory is being watched.")
+	fmt.Fprintf(out, "polyfield_watcher_up %d\n", boolValue(health.Watching))
+	metric("polyfield_watcher_errors_total", "counter", "Errors reported by the directory watcher.")
+	fmt.Fprintf(out, "polyfield_watcher_errors_total %d\n", m.watcherErrors)
+	metric("polyfield_files_watched", "gauge", "Result files in the results directory.")
+	fmt.Fprintf(out, "polyfield_files_watched %d\n", health.FilesWatched)
+	metric("polyfield_parses_total", "counter", "Result files parsed, by outcome.")
+	fmt.Fprintf(out, "polyfield_parses_total{result=\"success\"} %d\n", m.parseSuccesses)
+	fmt.Fprintf(out, "polyfield_parses_total{result=\"failure\"} %d\n", m.parseFailures)
+	metric("polyfield_parse_duration_seconds", "summary", "Time spent parsing result files.")
+	fmt.Fprintf(out, "polyfield_parse_duration_seconds_sum %g\n", m.parseSeconds)
+	fmt.Fprintf(out, "polyfield_parse_duration_seconds_count %d\n", m.parseSuccesses+m.parseFailures)
+	metric("polyfield_parse_cache_lookups_total", "counter", "Parse cache lookups, by outcome.")
+	fmt.Fprintf(out, "polyfield_parse_cache_lookups_total{result=\"hit\"} %d\n", m.cacheHits)
+	fmt.Fprintf(out, "polyfield_parse_cache_lookups_total{result=\"miss\"} %d\n", m.cacheMisses)
+	metric("polyfield_parse_cache_hit_ratio", "gauge", "Share of parse cache lookups that were hits since the server started.")
+	ratio := 0.0
+	if lookups := m.cacheHits + m.cacheMisses; lookups > 0 {
+		ratio = float64(m.cacheHits) / float64(lookups)
+	}
+	fmt.Fprintf(out, "polyfield_parse_cache_hit_ratio %g\n", ratio)
+	metric("polyfield_displays", "gauge", "Registered web displays, by state.")
+	fmt.Fprintf(out, "polyfield_displays{state=\"online\"} %d\n", online)
+	fmt.Fprintf(out, "polyfield_displays{state=\"offline\"} %d\n", offline)
+	if !m.lastResult.IsZero() {
+		metric("polyfield_last_result_timestamp_seconds", "gauge", "Unix time the newest result was saved.")
+		fmt.Fprintf(out, "polyfield_last_result_timestamp_seconds %d\n", m.lastResult.Unix())
+		metric("polyfield_seconds_since_last_result", "gauge", "Seconds since the newest result was saved.")
+		fmt.Fprintf(out, "polyfield_seconds_since_last_result %d\n", int64(time.Since(m.lastResult).Seconds()))
+	}
+
+	metric("polyfield_http_requests_total", "counter", "HTTP requests handled, by method, route and status code.")
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		fmt.Fprintf(out, "polyfield_http_requests_total{method=%q,route=%q,code=\"%d\"} %d\n",
+			key.method, key.route, key.status, m.requests[key])
+	}
+}
+
+// countRequests is Fiber middleware that counts requests for /metrics.
+func (a *App) countRequests(c *fiber.Ctx) error {
+	err := c.Next()
+	status := c.Response().StatusCode()
+	if err != nil {
+		// The error handler sets the status after the middleware returns.
+		status = fiber.StatusInternalServerError
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		}
+	}
+	// Static files are served by middleware mounted at the root, so count as '/'.
+	a.metrics.recordRequest(c.Method(), c.Route().Path, status)
+	return err
+}
+
+// registerMetricsRoutes adds the health and metrics endpoints to the Fiber server.
+// Both are open, so that a local dashboard can poll them without a key.
+func registerMetricsRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/healthz", func(c *fiber.Ctx) error {
+		health := app.GetHealth()
+		if health.Status != "ok" {
+			c.Status(503)
+		}
+		return c.JSON(health)
+	})
+	fiberApp.Get("/metrics", func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "text/plain; version=0.0.4")
+		app.writeMetrics(c.Response().BodyWriter())
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseResultCached(t *testing.T) {
+	dir := t.TempDir()
+	app := NewApp()
+	path := writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.01")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parse := func(want string) {
+		t.Helper()
+		data, err := app.parseResultCached(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := data.Competitors[0].Time; got != want {
+			t.Errorf("time = %q, want %q", got, want)
+		}
+		// Changing the copy returned mustn't change the cached parse.
+		data.Competitors[0].Time = "DQ"
+	}
+	parse("12.01")
+	parse("12.01")
+	if app.metrics.cacheHits != 1 || app.metrics.cacheMisses != 1 {
+		t.Errorf("hits, misses = %d, %d, want 1, 1", app.metrics.cacheHits, app.metrics.cacheMisses)
+	}
+
+	// Same size and modification time, different contents.
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,11.99")
+	if err := os.Chtimes(path, time.Time{}, info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	parse("11.99")
+
+	os.Remove(path)
+	app.forgetParse(path)
+	if _, ok := app.parseCache[path]; ok {
+		t.Error("parse of removed file still cached")
+	}
+	if _, err := app.parseResultCached(path); err == nil {
+		t.Error("parseResultCached() of removed file succeeded")
+	}
+}
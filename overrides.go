@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// overridesFile stores manual result corrections in the monitored directory.
+const overridesFile = "polyfield-overrides.json"
+
+// ResultOverride patches one competitor, matched by bib, in a result file.
+// An empty FileName applies the override to every result file. Empty string
+// fields leave the parsed value unchanged.
+type ResultOverride struct {
+	FileName    string `json:"fileName"`
+	Bib         string `json:"bib"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	Affiliation string `json:"affiliation"`
+	Status      string `json:"status"` // 'DQ', 'DNF' or 'DNS'
+	Hidden      bool   `json:"hidden"`
+}
+
+func (o ResultOverride) matches(fileName string, c Competitor) bool {
+	return (o.FileName == "" || o.FileName == fileName) && o.Bib == strings.TrimSpace(c.ID)
+}
+
+// loadOverrides reads the overrides file from dir. A missing file is not an error.
+func loadOverrides(dir string) ([]ResultOverride, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, overridesFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var overrides []ResultOverride
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", overridesFile, err)
+	}
+	resultsLog.Info("Loaded result overrides", "count", len(overrides), "file", overridesFile)
+	return overrides, nil
+}
+
+// saveOverrides writes the overrides file to dir.
+func saveOverrides(dir string, overrides []ResultOverride) error {
+	raw, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode overrides: %v", err)
+	}
+	// Write to a temporary file first so a crash mid-write can't lose the corrections.
+	path := filepath.Join(dir, overridesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return fmt.Errorf("failed to save overrides: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// initOverrides loads the overrides for the monitored directory.
+func (a *App) initOverrides() {
+	if a.monitoredDir == "" {
+		return
+	}
+	overrides, err := loadOverrides(a.monitoredDir)
+	if err != nil {
+		resultsLog.Error("Failed to load overrides", "error", err)
+	}
+	a.mu.Lock()
+	a.overrides = overrides
+	a.mu.Unlock()
+}
+
+// patchName applies the name and club corrections of an override to c.
+func (o ResultOverride) patchName(c *Competitor) {
+	if o.FirstName != "" {
+		c.FirstName = o.FirstName
+	}
+	if o.LastName != "" {
+		c.LastName = o.LastName
+	}
+	if o.Affiliation != "" {
+		c.Affiliation = o.Affiliation
+	}
+}
+
+// applyOverrides patches the competitors of data with the matching overrides.
+// Hidden competitors are removed, DNS competitors move to data.DNS, and DQ/DNF
+// competitors lose their place and move to the end, as the parsers do for
+// results from the timing system. The remaining places are then renumbered so
+// they have no gaps.
+func (a *App) applyOverrides(data *LifData) {
+	a.mu.Lock()
+	overrides := a.overrides
+	a.mu.Unlock()
+	if len(overrides) == 0 {
+		return
+	}
+
+	// Names are corrected for competitors who didn't start too, as exports list them.
+	var dns []Competitor
+	for _, c := range data.DNS {
+		hidden := false
+		for _, o := range overrides {
+			if o.matches(data.FileName, c) {
+				o.patchName(&c)
+				hidden = hidden || o.Hidden
+			}
+		}
+		if !hidden {
+			dns = append(dns, c)
+		}
+	}
+
+	competitors := make([]Competitor, 0, len(data.Competitors))
+	unplaced := false // whether an override removed a competitor or took their place
+	for _, c := range data.Competitors {
+		hidden, didNotStart := false, false
+		for _, o := range overrides {
+			if !o.matches(data.FileName, c) {
+				continue
+			}
+			o.patchName(&c)
+			switch strings.ToUpper(o.Status) {
+			case "DQ", "DNF":
+				c.Time = strings.ToUpper(o.Status)
+				if c.Place != "" {
+					c.Place = ""
+					unplaced = true
+				}
+			case "DNS":
+				didNotStart = true
+			}
+			if o.Hidden {
+				hidden = true
+			}
+		}
+		if hidden || didNotStart {
+			unplaced = unplaced || c.Place != ""
+			if !hidden {
+				c.Place, c.Time = "", ""
+				dns = append(dns, c)
+			}
+			continue
+		}
+		competitors = append(competitors, c)
+	}
+	data.DNS = dns
+	data.Competitors = orderCompetitors(competitors)
+	if unplaced {
+		renumberPlaces(data.Competitors)
+	}
+}
+
+// renumberPlaces renumbers placed competitors 1, 2, 3... in order. Competitors who
+// shared a place keep sharing it, and the place after a tie is skipped as usual.
+func renumberPlaces(competitors []Competitor) {
+	placed, prevOld, prevNew := 0, "", ""
+	for i := range competitors {
+		old := competitors[i].Place
+		if old == "" {
+			continue
+		}
+		placed++
+		if old != prevOld {
+			prevNew = strconv.Itoa(placed)
+		}
+		competitors[i].Place = prevNew
+		prevOld = old
+	}
+}
+
+// parseResult parses a result file and applies the manual overrides.
+func (a *App) parseResult(path string) (*LifData, error) {
+	data, err := a.parseResultCached(path)
+	if err != nil {
+		return nil, err
+	}
+	a.applyOverrides(data)
+	return data, nil
+}
+
+// describeOverride formats an override for the audit journal.
+func describeOverride(o ResultOverride) string {
+	parts := []string{"bib " + o.Bib}
+	for _, field := range []struct{ name, value string }{
+		{"firstName", o.FirstName}, {"lastName", o.LastName}, {"affiliation", o.Affiliation}, {"status", o.Status},
+	} {
+		if field.value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%q", field.name, field.value))
+		}
+	}
+	if o.Hidden {
+		parts = append(parts, "hidden")
+	}
+	return strings.Join(parts, " ")
+}
+
+// GetOverrides returns the manual result overrides for the monitored directory.
+func (a *App) GetOverrides() []ResultOverride {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	overrides := a.overrides
+	if a.archive != nil {
+		overrides = a.archive.overrides
+	}
+	result := make([]ResultOverride, len(overrides))
+	copy(result, overrides)
+	return result
+}
+
+// SetOverride adds an override, or replaces the existing one for the same file and bib.
+func (a *App) SetOverride(override ResultOverride) error {
+	return a.setOverride(sourceDesktop, override)
+}
+
+// setOverride is SetOverride, audited against source.
+func (a *App) setOverride(source string, override ResultOverride) error {
+	if err := a.archiveReadOnly(); err != nil {
+		return err
+	}
+	if a.monitoredDir == "" {
+		return fmt.Errorf("no directory selected")
+	}
+	override.Bib = strings.TrimSpace(override.Bib)
+	if override.Bib == "" {
+		return fmt.Errorf("bib is required")
+	}
+	override.Status = strings.ToUpper(strings.TrimSpace(override.Status))
+	switch override.Status {
+	case "", "DQ", "DNF", "DNS":
+	default:
+		return fmt.Errorf("unknown status %q, expected DQ, DNF or DNS", override.Status)
+	}
+	a.overridesMu.Lock()
+	defer a.overridesMu.Unlock()
+	a.mu.Lock()
+	overrides := make([]ResultOverride, 0, len(a.overrides)+1)
+	for _, o := range a.overrides {
+		if o.FileName != override.FileName || o.Bib != override.Bib {
+			overrides = append(overrides, o)
+		}
+	}
+	a.mu.Unlock()
+	overrides = append(overrides, override)
+	if err := saveOverrides(a.monitoredDir, overrides); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.overrides = overrides
+	a.mu.Unlock()
+	a.audit(AuditEntry{Action: auditOverrideSet, Source: source, File: override.FileName, Detail: describeOverride(override)})
+	resultsLog.Info("Override set", "file", override.FileName, "bib", override.Bib)
+	return nil
+}
+
+// RemoveOverride deletes the override for the given file and bib.
+func (a *App) RemoveOverride(fileName string, bib string) error {
+	return a.removeOverride(sourceDesktop, fileName, bib)
+}
+
+// removeOverride is RemoveOverride, audited against source.
+func (a *App) removeOverride(source string, fileName string, bib string) error {
+	if err := a.archiveReadOnly(); err != nil {
+		return err
+	}
+	if a.monitoredDir == "" {
+		return fmt.Errorf("no directory selected")
+	}
+	a.overridesMu.Lock()
+	defer a.overridesMu.Unlock()
+	a.mu.Lock()
+	overrides := make([]ResultOverride, 0, len(a.overrides))
+	found := false
+	for _, o := range a.overrides {
+		if o.FileName == fileName && o.Bib == bib {
+			found = true
+			continue
+		}
+		overrides = append(overrides, o)
+	}
+	a.mu.Unlock()
+	if !found {
+		return fmt.Errorf("no override for file %q bib %s", fileName, bib)
+	}
+	if err := saveOverrides(a.monitoredDir, overrides); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.overrides = overrides
+	a.mu.Unlock()
+	a.audit(AuditEntry{Action: auditOverrideRemoved, Source: source, File: fileName, Detail: "bib " + bib})
+	resultsLog.Info("Override removed", "file", fileName, "bib", bib)
+	return nil
+}
+
+// registerOverrideRoutes adds the override endpoints to the Fiber server.
+func registerOverrideRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/overrides", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetOverrides())
+	})
+	fiberApp.Post("/overrides", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var override ResultOverride
+		if err := c.BodyParser(&override); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.setOverride(app.requestSource(c), override); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Delete("/overrides", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.removeOverride(app.requestSource(c), c.Query("file"), c.Query("bib")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestApplyOverridesRenumbersPlaces(t *testing.T) {
+	field := []Competitor{
+		{Place: "1", ID: "1", Time: "12.01"},
+		{Place: "2", ID: "2", Time: "12.10"},
+		{Place: "3", ID: "3", Time: "12.20"},
+		{Place: "3", ID: "4", Time: "12.20"},
+		{Place: "5", ID: "5", Time: "12.40"},
+		{Place: "", ID: "6", Time: "DNF"},
+	}
+	type placing struct{ id, place, time string }
+
+	tests := []struct {
+		name      string
+		overrides []ResultOverride
+		want      []placing
+	}{
+		{
+			name:      "name change keeps places",
+			overrides: []ResultOverride{{Bib: "2", FirstName: "Bella"}},
+			want: []placing{
+				{"1", "1", "12.01"}, {"2", "2", "12.10"}, {"3", "3", "12.20"}, {"4", "3", "12.20"}, {"5", "5", "12.40"}, {"6", "", "DNF"},
+			},
+		},
+		{
+			name:      "disqualified",
+			overrides: []ResultOverride{{Bib: "2", Status: "DQ"}},
+			want: []placing{
+				{"1", "1", "12.01"}, {"3", "2", "12.20"}, {"4", "2", "12.20"}, {"5", "4", "12.40"}, {"2", "", "DQ"}, {"6", "", "DNF"},
+			},
+		},
+		{
+			name:      "did not start",
+			overrides: []ResultOverride{{Bib: "1", Status: "DNS"}},
+			want: []placing{
+				{"2", "1", "12.10"}, {"3", "2", "12.20"}, {"4", "2", "12.20"}, {"5", "4", "12.40"}, {"6", "", "DNF"},
+			},
+		},
+		{
+			name:      "hidden, one of a tie",
+			overrides: []ResultOverride{{Bib: "3", Hidden: true}},
+			want: []placing{
+				{"1", "1", "12.01"}, {"2", "2", "12.10"}, {"4", "3", "12.20"}, {"5", "4", "12.40"}, {"6", "", "DNF"},
+			},
+		},
+		{
+			name:      "other file",
+			overrides: []ResultOverride{{FileName: "other.lif", Bib: "1", Status: "DQ"}},
+			want: []placing{
+				{"1", "1", "12.01"}, {"2", "2", "12.10"}, {"3", "3", "12.20"}, {"4", "3", "12.20"}, {"5", "5", "12.40"}, {"6", "", "DNF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewApp()
+			app.overrides = tt.overrides
+			data := &LifData{FileName: "100m.lif", Competitors: append([]Competitor(nil), field...)}
+			app.applyOverrides(data)
+			var got []placing
+			for _, c := range data.Competitors {
+				got = append(got, placing{c.ID, c.Place, c.Time})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyOverrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOverrideStatus(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	app := newTestApp(t, dir, false)
+	fiberApp := fiber.New()
+	registerOverrideRoutes(fiberApp, app)
+
+	tests := []struct {
+		status     string
+		wantCode   int
+		wantStatus string
+	}{
+		{"", 200, ""},
+		{" dnf ", 200, "DNF"},
+		{"dq", 200, "DQ"},
+		{"DNS", 200, "DNS"},
+		{"DSQ", 400, ""},
+		{"retired", 400, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			body := fmt.Sprintf(`{"bib":"101","status":%q}`, tt.status)
+			req := httptest.NewRequest("POST", "/overrides", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Operator-Token", app.GetOperatorToken())
+			resp, err := fiberApp.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status code = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode != 200 {
+				return
+			}
+			saved, err := loadOverrides(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(saved) != 1 || saved[0].Status != tt.wantStatus {
+				t.Errorf("saved overrides = %+v, want one with status %q", saved, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSetOverrideSaveFailure(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	app := newTestApp(t, dir, false)
+	if err := app.SetOverride(ResultOverride{Bib: "101", Status: "DQ"}); err != nil {
+		t.Fatal(err)
+	}
+	// A directory in the way of the temporary file makes the next save fail.
+	if err := os.Mkdir(filepath.Join(dir, overridesFile+".tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SetOverride(ResultOverride{Bib: "102", Status: "DNF"}); err == nil {
+		t.Error("SetOverride() succeeded with an unwritable overrides file")
+	}
+	if err := app.RemoveOverride("", "101"); err == nil {
+		t.Error("RemoveOverride() succeeded with an unwritable overrides file")
+	}
+	got := app.GetOverrides()
+	if len(got) != 1 || got[0].Bib != "101" {
+		t.Errorf("overrides after failed saves = %+v, want only bib 101", got)
+	}
+	entries, err := app.GetAuditLog(AuditQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Detail, "102") || entry.Action == auditOverrideRemoved {
+			t.Errorf("failed change was audited: %+v", entry)
+		}
+	}
+}
+
+func TestApplyOverridesDNS(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLif(t, dir, "a.lif", "Women 100m",
+		"1,101,4,Smith,Alice,Kingston,12.01",
+		"2,102,5,Jones,Bella,Sutton,12.30",
+		"DNS,103,6,Brown,Cara,Herne Hill,",
+		"DNS,104,7,Green,Dana,Woking,")
+	app := NewApp()
+	app.overrides = []ResultOverride{
+		{Bib: "102", Status: "DNS"},
+		{Bib: "103", FirstName: "Carla"},
+		{Bib: "104", Hidden: true},
+	}
+	data, err := app.parseResult(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type row struct{ bib, name, place, time string }
+	var competitors, dns []row
+	for _, c := range data.Competitors {
+		competitors = append(competitors, row{c.ID, c.FirstName, c.Place, c.Time})
+	}
+	for _, c := range data.DNS {
+		dns = append(dns, row{c.ID, c.FirstName, c.Place, c.Time})
+	}
+	if want := []row{{"101", "Alice", "1", "12.01"}}; !reflect.DeepEqual(competitors, want) {
+		t.Errorf("competitors = %v, want %v", competitors, want)
+	}
+	// Rows with DNS as their place come first, with their name corrected, then
+	// competitors given the DNS status. Hidden competitors are left out.
+	if want := []row{{"103", "Carla", "", ""}, {"102", "Bella", "", ""}}; !reflect.DeepEqual(dns, want) {
+		t.Errorf("DNS = %v, want %v", dns, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// parsedFile is one file's output from the parse command.
+type parsedFile struct {
+	Path   string       `json:"path"`
+	Data   *LifData     `json:"data"`
+	Report *ParseReport `json:"report"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// expandResultPaths replaces each directory in paths with the result files inside it.
+func expandResultPaths(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isResultFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// runParse parses result files offline and prints what the parser made of them,
+// for triaging odd files from different timing systems. It returns an error if
+// any file failed to parse.
+func runParse(args []string) error {
+	flags := flag.NewFlagSet("parse", flag.ContinueOnError)
+	format := flags.String("format", "table", "output format, 'table' or 'json'")
+	verbose := flags.Bool("v", false, "also print the parser's debug log, including every row read")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s parse [-format table|json] [-v] file-or-directory...\n", filepath.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format %q, use table or json", *format)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no files given")
+	}
+	paths, err := expandResultPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+	if *verbose {
+		logLevel.Set(slog.LevelDebug)
+	} else {
+		logLevel.Set(slog.LevelError)
+	}
+
+	var parsed []parsedFile
+	failed := 0
+	for _, path := range paths {
+		data, report, err := parseFileWithReport(path)
+		file := parsedFile{Path: path, Data: data, Report: report}
+		if err != nil {
+			file.Error = err.Error()
+			failed++
+		}
+		parsed = append(parsed, file)
+	}
+
+	if err := writeParsedFiles(os.Stdout, *format, parsed); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to parse", failed, len(parsed))
+	}
+	return nil
+}
+
+// writeParsedFiles writes the parse command's output for parsed files in format,
+// 'table' or 'json'.
+func writeParsedFiles(out io.Writer, format string, parsed []parsedFile) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(parsed)
+	}
+	for i, file := range parsed {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		printParsedFile(out, file)
+	}
+	return nil
+}
+
+// printParsedFile prints a parsed file and its diagnostics as text.
+func printParsedFile(out io.Writer, file parsedFile) {
+	report := file.Report
+	fmt.Fprintf(out, "== %s\n", file.Path)
+	charset := report.Charset
+	if charset == "" {
+		charset = "unknown"
+	}
+	fmt.Fprintf(out, "Format: %s  Charset: %s  Rows: %d  Competitors: %d  Skipped: %d\n",
+		report.Format, charset, report.Rows, report.Competitors, report.skippedRows())
+	if file.Error != "" {
+		fmt.Fprintf(out, "Error: %s\n", file.Error)
+	}
+	if file.Data != nil {
+		fmt.Fprintf(out, "Event: %s\n", file.Data.EventName)
+		if file.Data.Round != "" || file.Data.Heat != "" {
+			fmt.Fprintf(out, "Round: %s, heat: %s\n", file.Data.Round, file.Data.Heat)
+		}
+		if file.Data.Wind != "" {
+			fmt.Fprintf(out, "Wind: %s\n", file.Data.Wind)
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Place\tID\tName\tAffiliation\tTime")
+		for _, c := range file.Data.Competitors {
+			name := strings.TrimSpace(c.FirstName + " " + c.LastName)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Place, c.ID, name, c.Affiliation, c.Time)
+		}
+		w.Flush()
+	}
+	if len(report.Issues) > 0 {
+		fmt.Fprintln(out, "Diagnostics:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, issue := range report.Issues {
+			action := "kept"
+			if issue.Skipped {
+				action = "skipped"
+			}
+			fmt.Fprintf(w, "  row %d\t%s\t%s\t%s\t%q\n", issue.Row, action, issue.Kind, issue.Reason, strings.Join(issue.Raw, " | "))
+		}
+		w.Flush()
+	}
+}
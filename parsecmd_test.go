@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteParsedFiles(t *testing.T) {
+	dir := t.TempDir()
+	var parsed []parsedFile
+	for _, path := range []string{
+		writeLif(t, dir, "a.lif", "Women 100m", "1,101,4,Smith,Alice,Kingston,12.01"),
+		writeLif(t, dir, "b.lif", "Women 200m", "1,102,5,Jones,Bella,Sutton,25.10"),
+	} {
+		data, report, err := parseFileWithReport(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, parsedFile{Path: path, Data: data, Report: report})
+	}
+
+	var out bytes.Buffer
+	if err := writeParsedFiles(&out, "table", parsed); err != nil {
+		t.Fatal(err)
+	}
+	files := strings.Split(out.String(), "\n\n== ")
+	if len(files) != 2 {
+		t.Fatalf("table output has %d files separated by a blank line, want 2:\n%s", len(files), out.String())
+	}
+	for i, want := range []string{"Alice Smith", "Bella Jones"} {
+		if !strings.Contains(files[i], want) {
+			t.Errorf("file %d output doesn't list %s:\n%s", i+1, want, files[i])
+		}
+	}
+
+	out.Reset()
+	if err := writeParsedFiles(&out, "json", parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "[") || !strings.Contains(out.String(), `"Women 200m"`) {
+		t.Errorf("json output = %s", out.String())
+	}
+}
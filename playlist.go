@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultResultHold is how long a new result interrupts a playlist when the playlist doesn't say.
+const defaultResultHold = 30
+
+// PlaylistItem is one entry in a display playlist.
+type PlaylistItem struct {
+	Type     string `json:"type"`     // 'image', 'text', 'latestResult', 'multiResult' or 'leagueTable'
+	MediaID  string `json:"mediaId"`  // Media library ID for 'image' items
+	Text     string `json:"text"`     // Message for 'text' items
+	Duration int    `json:"duration"` // Seconds to show the item for
+	Start    string `json:"start"`    // Optional time-of-day window start, 'HH:MM'
+	End      string `json:"end"`      // Optional time-of-day window end, 'HH:MM'
+}
+
+// Playlist is an ordered rotation of display content assigned to display channels.
+type Playlist struct {
+	Name       string         `json:"name"`
+	Items      []PlaylistItem `json:"items"`
+	ResultHold int            `json:"resultHold"` // Seconds a new result interrupts the rotation for
+}
+
+// playlistRun tracks where a channel is in its playlist.
+type playlistRun struct {
+	playlist    string
+	index       int
+	itemStarted time.Time
+	holdUntil   time.Time // A new result is being shown until this time
+}
+
+// parseClock parses an 'HH:MM' time of day into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// activeAt reports whether the item's time-of-day window includes now. Items
+// without a window are always active; a window whose end is before its start
+// runs past midnight.
+func (item PlaylistItem) activeAt(now time.Time) bool {
+	if item.Start == "" && item.End == "" {
+		return true
+	}
+	minute := now.Hour()*60 + now.Minute()
+	start, end := 0, 24*60
+	if item.Start != "" {
+		start, _ = parseClock(item.Start)
+	}
+	if item.End != "" {
+		end, _ = parseClock(item.End)
+	}
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// validate checks a playlist before it is saved.
+func (p *Playlist) validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("playlist name is required")
+	}
+	if len(p.Items) == 0 {
+		return fmt.Errorf("playlist %q has no items", p.Name)
+	}
+	if p.ResultHold < 0 {
+		return fmt.Errorf("result hold can't be negative")
+	}
+	for i, item := range p.Items {
+		switch item.Type {
+		case "image", "text", "latestResult", "multiResult", "leagueTable":
+		default:
+			return fmt.Errorf("item %d: unknown type %q", i+1, item.Type)
+		}
+		if item.Type == "image" && item.MediaID == "" {
+			return fmt.Errorf("item %d: no image selected", i+1)
+		}
+		if item.Duration <= 0 {
+			return fmt.Errorf("item %d: duration must be at least 1 second", i+1)
+		}
+		for _, clock := range []string{item.Start, item.End} {
+			if clock == "" {
+				continue
+			}
+			if _, err := parseClock(clock); err != nil {
+				return fmt.Errorf("item %d: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyPlaylistItem sets a channel's display state to show a playlist item.
+// The caller must hold a.mu.
+func (a *App) applyPlaylistItem(state *DisplayState, item PlaylistItem) {
+	switch item.Type {
+	case "image":
+		state.Mode = "screensaver"
+		state.ImageID = item.MediaID
+		state.SlideshowIDs = nil
+		state.ActiveText = ""
+		state.View = ""
+	case "text":
+		state.Mode = "text"
+		state.ActiveText = item.Text
+		state.SlideshowIDs = nil
+		state.View = ""
+	case "latestResult":
+		state.Mode = "lif"
+		state.View = "fullscreen"
+		state.CurrentLIF = a.latestData
+	case "multiResult":
+		state.Mode = "lif"
+		state.View = "multi"
+	case "leagueTable":
+		state.Mode = "lif"
+		state.View = "league"
+	}
+}
+
+// stopPlaylist takes a channel off its playlist and hands it back to manual
+// control, clearing the view, result, text and slideshow the playlist put on it.
+// The caller must hold a.mu.
+func stopPlaylist(state *DisplayState) {
+	state.Playlist = ""
+	state.Mode = "lif"
+	state.View = ""
+	state.CurrentLIF = nil
+	state.ActiveText = ""
+	state.SlideshowIDs = nil
+}
+
+// advancePlaylists moves every channel with a playlist on to its next item when the
+// current one has run its duration or left its time window.
+func (a *App) advancePlaylists(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for name, state := range a.channels {
+		playlist, ok := a.playlists[state.Playlist]
+		if state.Playlist == "" || !ok {
+			delete(a.playlistRuns, name)
+			continue
+		}
+		run, ok := a.playlistRuns[name]
+		if !ok || run.playlist != playlist.Name {
+			run = &playlistRun{playlist: playlist.Name, index: -1}
+			a.playlistRuns[name] = run
+		}
+		if now.Before(run.holdUntil) {
+			continue
+		}
+		if run.index >= 0 && run.index < len(playlist.Items) {
+			item := playlist.Items[run.index]
+			if run.holdUntil.IsZero() && item.activeAt(now) &&
+				now.Sub(run.itemStarted) < time.Duration(item.Duration)*time.Second {
+				continue
+			}
+		}
+		run.holdUntil = time.Time{}
+		// Find the next item whose time window is open.
+		for step := 1; step <= len(playlist.Items); step++ {
+			next := (run.index + step) % len(playlist.Items)
+			if playlist.Items[next].activeAt(now) {
+				run.index = next
+				run.itemStarted = now
+				a.applyPlaylistItem(state, playlist.Items[next])
+				break
+			}
+		}
+	}
+}
+
+// runPlaylists drives the playlist rotation for all display channels.
+func (a *App) runPlaylists() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		a.advancePlaylists(now)
+		a.auditDisplays(sourcePlaylist)
+	}
+}
+
+// interruptPlaylists shows a new result on every channel running a playlist and
+// pauses the rotation for the playlist's result hold time.
+func (a *App) interruptPlaylists(data *LifData) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for name, state := range a.channels {
+		playlist, ok := a.playlists[state.Playlist]
+		if !ok {
+			continue
+		}
+		hold := playlist.ResultHold
+		if hold == 0 {
+			hold = defaultResultHold
+		}
+		run, ok := a.playlistRuns[name]
+		if !ok {
+			run = &playlistRun{playlist: playlist.Name, index: -1}
+			a.playlistRuns[name] = run
+		}
+		run.holdUntil = now.Add(time.Duration(hold) * time.Second)
+		state.Mode = "lif"
+		state.View = "fullscreen"
+		state.CurrentLIF = data
+	}
+}
+
+// GetPlaylists returns the saved playlists sorted by name.
+func (a *App) GetPlaylists() []Playlist {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	result := make([]Playlist, 0, len(a.playlists))
+	for _, p := range a.playlists {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// SavePlaylist creates or replaces a playlist.
+func (a *App) SavePlaylist(playlist Playlist) error {
+	if err := playlist.validate(); err != nil {
+		return err
+	}
+	for i, item := range playlist.Items {
+		if item.Type != "image" {
+			continue
+		}
+		if _, _, err := a.mediaPath(item.MediaID); err != nil {
+			return fmt.Errorf("item %d: %v", i+1, err)
+		}
+	}
+	a.mu.Lock()
+	a.playlists[playlist.Name] = &playlist
+	// Restart channels running the old version of the playlist.
+	for name, run := range a.playlistRuns {
+		if run.playlist == playlist.Name {
+			delete(a.playlistRuns, name)
+		}
+	}
+	a.mu.Unlock()
+	displayLog.Info("Playlist saved", "playlist", playlist.Name, "items", len(playlist.Items))
+	a.scheduleSave()
+	return nil
+}
+
+// DeletePlaylist removes a playlist and unassigns it from any channels.
+func (a *App) DeletePlaylist(name string) error {
+	a.mu.Lock()
+	if _, ok := a.playlists[name]; !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("no playlist named %q", name)
+	}
+	delete(a.playlists, name)
+	for _, state := range a.channels {
+		if state.Playlist == name {
+			stopPlaylist(state)
+		}
+	}
+	a.mu.Unlock()
+	displayLog.Info("Playlist deleted", "playlist", name)
+	a.scheduleSave()
+	return nil
+}
+
+// AssignPlaylist runs a playlist on a display channel. An empty playlist name stops it.
+func (a *App) AssignPlaylist(channel string, playlist string) error {
+	return a.assignPlaylist(sourceDesktop, channel, playlist)
+}
+
+// assignPlaylist is AssignPlaylist, audited against source.
+func (a *App) assignPlaylist(source string, channel string, playlist string) error {
+	if playlist != "" {
+		a.mu.Lock()
+		_, ok := a.playlists[playlist]
+		a.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("no playlist named %q", playlist)
+		}
+	}
+	err := a.updateChannel(source, channel, func(state *DisplayState) {
+		if playlist == "" && state.Playlist != "" {
+			stopPlaylist(state)
+		}
+		state.Playlist = playlist
+	})
+	if err == nil {
+		displayLog.Info("Playlist assigned", "channel", normaliseChannel(channel), "playlist", playlist)
+	}
+	return err
+}
+
+// registerPlaylistRoutes adds the playlist endpoints to the Fiber server.
+func registerPlaylistRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/playlists", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		return c.JSON(app.GetPlaylists())
+	})
+	fiberApp.Post("/playlists", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var playlist Playlist
+		if err := c.BodyParser(&playlist); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SavePlaylist(playlist); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Delete("/playlists/:name", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		if err := app.DeletePlaylist(c.Params("name")); err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/channels/:name/playlist", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var req struct {
+			Playlist string `json:"playlist"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.assignPlaylist(app.requestSource(c), c.Params("name"), req.Playlist); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyPlaylistItem(t *testing.T) {
+	latest := &LifData{FileName: "latest.lif"}
+	tests := []struct {
+		item PlaylistItem
+		want DisplayState
+	}{
+		{PlaylistItem{Type: "image", MediaID: "m1"}, DisplayState{Mode: "screensaver", ImageID: "m1"}},
+		{PlaylistItem{Type: "text", Text: "Welcome"}, DisplayState{Mode: "text", ActiveText: "Welcome", ImageID: "old"}},
+		{PlaylistItem{Type: "latestResult"}, DisplayState{Mode: "lif", View: "fullscreen", CurrentLIF: latest, ActiveText: "old", ImageID: "old", SlideshowIDs: []string{"s1", "s2"}}},
+		{PlaylistItem{Type: "multiResult"}, DisplayState{Mode: "lif", View: "multi", ActiveText: "old", ImageID: "old", SlideshowIDs: []string{"s1", "s2"}}},
+		{PlaylistItem{Type: "leagueTable"}, DisplayState{Mode: "lif", View: "league", ActiveText: "old", ImageID: "old", SlideshowIDs: []string{"s1", "s2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.item.Type, func(t *testing.T) {
+			app := NewApp()
+			app.latestData = latest
+			// Left over from whatever the channel showed before.
+			state := &DisplayState{Mode: "screensaver", View: "multi", ActiveText: "old", ImageID: "old", SlideshowIDs: []string{"s1", "s2"}}
+			app.applyPlaylistItem(state, tt.item)
+			if state.Mode != tt.want.Mode || state.View != tt.want.View || state.ActiveText != tt.want.ActiveText ||
+				state.ImageID != tt.want.ImageID || len(state.SlideshowIDs) != len(tt.want.SlideshowIDs) ||
+				state.CurrentLIF != tt.want.CurrentLIF {
+				t.Errorf("applyPlaylistItem() = %+v, want %+v", *state, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistItemActiveAt(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("15:04", clock)
+		return t
+	}
+	tests := []struct {
+		start, end, now string
+		want            bool
+	}{
+		{"", "", "03:00", true},
+		{"09:00", "12:30", "09:00", true},
+		{"09:00", "12:30", "12:30", false},
+		{"09:00", "", "08:59", false},
+		{"", "12:30", "08:59", true},
+		{"22:00", "02:00", "23:30", true},
+		{"22:00", "02:00", "01:59", true},
+		{"22:00", "02:00", "12:00", false},
+	}
+	for _, tt := range tests {
+		item := PlaylistItem{Start: tt.start, End: tt.end}
+		if got := item.activeAt(at(tt.now)); got != tt.want {
+			t.Errorf("window %q-%q at %s: activeAt() = %v, want %v", tt.start, tt.end, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestPlaylistValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []PlaylistItem
+		wantErr bool
+	}{
+		{"league table", []PlaylistItem{{Type: "leagueTable", Duration: 10}}, false},
+		{"unknown type", []PlaylistItem{{Type: "video", Duration: 10}}, true},
+		{"image without media", []PlaylistItem{{Type: "image", Duration: 10}}, true},
+		{"no duration", []PlaylistItem{{Type: "text", Text: "Hi"}}, true},
+		{"bad window", []PlaylistItem{{Type: "multiResult", Duration: 10, Start: "9am"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Playlist{Name: "Infield", Items: tt.items}
+			if err := p.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStopPlaylistRestoresManualControl(t *testing.T) {
+	now := time.Now()
+	newApp := func(t *testing.T) *App {
+		app := NewApp()
+		app.settingsFile = t.TempDir() + "/settings.json"
+		app.latestData = &LifData{FileName: "latest.lif"}
+		app.playlists["Between races"] = &Playlist{Name: "Between races", Items: []PlaylistItem{
+			{Type: "latestResult", Duration: 10},
+			{Type: "text", Text: "Welcome", Duration: 10},
+		}}
+		if err := app.AssignPlaylist(defaultChannel, "Between races"); err != nil {
+			t.Fatal(err)
+		}
+		app.advancePlaylists(now)
+		if state := app.channels[defaultChannel]; state.View != "fullscreen" || state.CurrentLIF == nil {
+			t.Fatalf("playlist not running: %+v", *state)
+		}
+		return app
+	}
+	manual := func(t *testing.T, app *App) {
+		t.Helper()
+		state := app.channels[defaultChannel]
+		if state.Playlist != "" || state.Mode != "lif" || state.View != "" || state.CurrentLIF != nil || state.ActiveText != "" || state.SlideshowIDs != nil {
+			t.Errorf("channel after the playlist stopped = %+v, want manual control", *state)
+		}
+		// The rotation doesn't pick the channel up again.
+		app.advancePlaylists(now.Add(time.Minute))
+		if state.View != "" || state.Mode != "lif" {
+			t.Errorf("channel after the next tick = %+v, want manual control", *state)
+		}
+	}
+
+	t.Run("unassigned", func(t *testing.T) {
+		app := newApp(t)
+		if err := app.AssignPlaylist(defaultChannel, ""); err != nil {
+			t.Fatal(err)
+		}
+		manual(t, app)
+	})
+	t.Run("deleted", func(t *testing.T) {
+		app := newApp(t)
+		if err := app.DeletePlaylist("Between races"); err != nil {
+			t.Fatal(err)
+		}
+		manual(t, app)
+	})
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Rankings submission formats.
+const (
+	rankingPowerOf10 = "powerof10" // UK rankings upload
+	rankingOpenTrack = "opentrack"
+)
+
+// rankingDistances are the track distances, in metres, that have an event code.
+var rankingDistances = map[int]bool{
+	50: true, 55: true, 60: true, 70: true, 75: true, 80: true, 100: true, 110: true, 150: true, 200: true,
+	300: true, 400: true, 600: true, 800: true, 1000: true, 1500: true, 2000: true, 3000: true,
+	5000: true, 10000: true, 20000: true,
+}
+
+var (
+	relayPattern    = regexp.MustCompile(`(\d)\s*x\s*(\d{2,4})\s*m?`)
+	distancePattern = regexp.MustCompile(`(?:^|[^a-z0-9])(\d{2,5})\s*(?:m|metres|meters)?\s*(h|hurdles|sc|s/c|steeple|steeplechase|walk|rw)?(?:[^a-z]|$)`)
+	milePattern     = regexp.MustCompile(`\bmile\b`)
+	ageGroupPattern = regexp.MustCompile(`^(u\d{2}|sen|senior|seniors|s|v\d{2}|m\d{2}|w\d{2})([mwbgf])?$`)
+)
+
+// RankingIssue is a row, or a whole event if Row is 0, that couldn't be exported.
+type RankingIssue struct {
+	File    string `json:"file"`
+	Event   string `json:"event"`
+	Row     int    `json:"row"` // Position in the results, from 1
+	Athlete string `json:"athlete"`
+	Problem string `json:"problem"`
+}
+
+// RankingReport is the outcome of a rankings export: how many rows were exported
+// and the validation report of the ones that weren't.
+type RankingReport struct {
+	Format string         `json:"format"`
+	Rows   int            `json:"rows"`
+	Issues []RankingIssue `json:"issues"`
+	Path   string         `json:"path,omitempty"` // Where the export was saved, from the desktop app
+}
+
+// rankingEntry is a result in a rankings submission.
+type rankingEntry struct {
+	Event       string // Event code, e.g. '100', '110H', '3000SC' or '4x100'
+	Gender      string // 'M' or 'W', empty if unknown
+	AgeGroup    string // e.g. 'U17', 'SEN' or 'V40', empty if unknown
+	Round       string
+	Heat        string
+	Place       string
+	Bib         string
+	FirstName   string
+	LastName    string
+	Club        string
+	Performance string // To the precision rankings expect, empty for a status
+	Wind        string // e.g. '+1.2', without the unit
+	Status      string // 'DQ', 'DNF' or 'DNS', empty for a performance
+	Date        time.Time
+}
+
+// rankingEvent returns the event code of an event from its name, and its distance
+// in metres, or false if the event isn't recognised.
+func rankingEvent(name string) (string, int, bool) {
+	lower := strings.ToLower(name)
+	if match := relayPattern.FindStringSubmatch(lower); match != nil {
+		legs, _ := strconv.Atoi(match[1])
+		leg, _ := strconv.Atoi(match[2])
+		return fmt.Sprintf("%dx%d", legs, leg), legs * leg, true
+	}
+	if milePattern.MatchString(lower) {
+		return "Mile", 1609, true
+	}
+	for _, match := range distancePattern.FindAllStringSubmatch(lower, -1) {
+		distance, _ := strconv.Atoi(match[1])
+		if !rankingDistances[distance] {
+			continue
+		}
+		code := strconv.Itoa(distance)
+		switch match[2] {
+		case "h", "hurdles":
+			code += "H"
+		case "sc", "s/c", "steeple", "steeplechase":
+			code += "SC"
+		case "walk", "rw":
+			code += "W"
+		}
+		return code, distance, true
+	}
+	return "", 0, false
+}
+
+// rankingCategory returns the gender ('M' or 'W') and age group of an event from
+// words in its name such as 'U17 Men', 'Senior Women', 'U15G', 'SM' or 'V40',
+// each empty if the name doesn't give it.
+func rankingCategory(name string) (string, string) {
+	gender, ageGroup := "", ""
+	genderOf := func(word string) string {
+		switch word {
+		case "m", "men", "man", "male", "mens", "boys", "boy":
+			return "M"
+		case "w", "women", "woman", "female", "womens", "ladies", "girls", "girl":
+			return "W"
+		}
+		return ""
+	}
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for i, word := range words {
+		if (word == "m" || word == "w") && i > 0 && strings.Trim(words[i-1], "0123456789") == "" {
+			continue // A distance such as '100 m', not a gender
+		}
+		if g := genderOf(word); g != "" {
+			gender = g
+			continue
+		}
+		match := ageGroupPattern.FindStringSubmatch(word)
+		if match == nil {
+			continue
+		}
+		group, suffix := match[1], match[2]
+		switch {
+		case group == "s" && suffix == "":
+			continue // Not a category on its own
+		case group == "s" || group == "sen" || group == "senior" || group == "seniors":
+			ageGroup = "SEN"
+		case group[0] == 'm' || group[0] == 'w':
+			// Masters, e.g. M35 or W40
+			ageGroup = "V" + group[1:]
+			gender = genderOf(group[:1])
+		default:
+			ageGroup = strings.ToUpper(group)
+		}
+		if suffix != "" {
+			gender = map[string]string{"m": "M", "b": "M", "w": "W", "g": "W", "f": "W"}[suffix]
+		}
+	}
+	return gender, ageGroup
+}
+
+// rankingPerformance returns a time to the precision rankings expect: hundredths
+// up to 10,000m, as the parser already rounds them, and whole seconds, rounded up,
+// beyond that.
+func rankingPerformance(value string, distance int) (string, error) {
+	value = strings.TrimSpace(value)
+	seconds, err := parseTimeString(value)
+	if err != nil || seconds <= 0 {
+		return "", fmt.Errorf("time %q can't be read", value)
+	}
+	if distance <= 10000 {
+		return value, nil
+	}
+	whole := int(math.Ceil(seconds - 0.000001))
+	if whole >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", whole/3600, whole%3600/60, whole%60), nil
+	}
+	return fmt.Sprintf("%d:%02d", whole/60, whole%60), nil
+}
+
+// rankingEntries turns the results of a session ('all', 'today', 'current', a
+// session ID, or empty for all) into rankings entries for a format, with the
+// rows that couldn't be exported.
+func (a *App) rankingEntries(format string, session string) ([]rankingEntry, []RankingIssue, error) {
+	if format != rankingPowerOf10 && format != rankingOpenTrack {
+		return nil, nil, fmt.Errorf("unknown format %q, use %s or %s", format, rankingPowerOf10, rankingOpenTrack)
+	}
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, nil, err
+	}
+	meet := a.GetMeet()
+	results, err = meet.filterResults(results, session, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	sorted := append([]*LifData(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ModifiedTime < sorted[j].ModifiedTime })
+
+	entries := []rankingEntry{}
+	issues := []RankingIssue{}
+	for _, data := range sorted {
+		eventIssue := func(problem string) {
+			issues = append(issues, RankingIssue{File: data.FileName, Event: data.EventName, Problem: problem})
+		}
+		code, distance, ok := rankingEvent(data.EventName)
+		if !ok {
+			eventIssue("unknown event: no distance in the event name")
+			continue
+		}
+		gender, ageGroup := rankingCategory(data.EventName)
+		if format == rankingPowerOf10 {
+			if gender == "" {
+				eventIssue("no gender in the event name, e.g. Men or Women")
+			}
+			if ageGroup == "" {
+				eventIssue("no age group in the event name, e.g. U17 or Senior")
+			}
+			if gender == "" || ageGroup == "" {
+				continue
+			}
+		}
+		for _, warning := range data.Warnings {
+			// Rows the parser skipped aren't in the results to export.
+			eventIssue(fmt.Sprintf("row %d of the file couldn't be read: %s", warning.Row, warning.Reason))
+		}
+		competitors := append([]Competitor(nil), data.Competitors...)
+		for _, c := range data.DNS {
+			c.Time = "DNS"
+			competitors = append(competitors, c)
+		}
+		for i, c := range competitors {
+			athlete := strings.TrimSpace(c.FirstName + " " + c.LastName)
+			rowIssue := func(problem string) {
+				issues = append(issues, RankingIssue{File: data.FileName, Event: data.EventName, Row: i + 1, Athlete: athlete, Problem: problem})
+			}
+			entry := rankingEntry{
+				Event:     code,
+				Gender:    gender,
+				AgeGroup:  ageGroup,
+				Round:     data.Round,
+				Heat:      data.Heat,
+				Place:     c.Place,
+				Bib:       strings.TrimSpace(c.ID),
+				FirstName: strings.TrimSpace(c.FirstName),
+				LastName:  strings.TrimSpace(c.LastName),
+				Club:      strings.TrimSpace(c.Affiliation),
+				Wind:      strings.TrimSpace(strings.TrimSuffix(data.Wind, "m/s")),
+				Status:    competitorStatus(c),
+				Date:      time.Unix(data.ModifiedTime, 0),
+			}
+			if entry.FirstName == "" || entry.LastName == "" {
+				rowIssue("missing first or last name")
+				continue
+			}
+			if entry.Status == "" {
+				performance, err := rankingPerformance(c.Time, distance)
+				if err != nil {
+					rowIssue(err.Error())
+					continue
+				}
+				entry.Performance = performance
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, issues, nil
+}
+
+// writeRankings writes rankings entries as a CSV file in a submission format.
+// The column layouts are pinned by the golden files in testdata; check them
+// against each site's current upload template before changing them.
+func writeRankings(out io.Writer, format string, entries []rankingEntry) error {
+	writer := csv.NewWriter(out)
+	if format == rankingPowerOf10 {
+		// A DQ, DNF or DNS goes in the position, with no performance.
+		writer.Write([]string{"Event", "Gender", "Age Group", "Round", "Heat", "Position", "Bib", "First Name", "Surname", "Club", "Performance", "Wind", "Date"})
+		for _, e := range entries {
+			position := e.Place
+			if e.Status != "" {
+				position = e.Status
+			}
+			writer.Write([]string{e.Event, e.Gender, e.AgeGroup, e.Round, e.Heat, position, e.Bib, e.FirstName, e.LastName, e.Club, e.Performance, e.Wind, e.Date.Format("02/01/2006")})
+		}
+	} else {
+		writer.Write([]string{"event_code", "gender", "age_group", "round", "heat", "place", "bib", "first_name", "last_name", "team", "performance", "wind", "status", "date"})
+		for _, e := range entries {
+			writer.Write([]string{e.Event, e.Gender, e.AgeGroup, e.Round, e.Heat, e.Place, e.Bib, e.FirstName, e.LastName, e.Club, e.Performance, e.Wind, e.Status, e.Date.Format("2006-01-02")})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// CheckRankings returns the validation report of a rankings export without
+// saving it: how many rows would be exported and which couldn't be.
+func (a *App) CheckRankings(format string, session string) (*RankingReport, error) {
+	entries, issues, err := a.rankingEntries(format, session)
+	if err != nil {
+		return nil, err
+	}
+	return &RankingReport{Format: format, Rows: len(entries), Issues: issues}, nil
+}
+
+// ExportRankings asks where to save the results of a session in a rankings
+// submission format ('powerof10' or 'opentrack') and writes them. It returns the
+// validation report, or nil if cancelled.
+func (a *App) ExportRankings(format string, session string) (*RankingReport, error) {
+	entries, issues, err := a.rankingEntries(format, session)
+	if err != nil {
+		return nil, err
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Rankings Submission",
+		DefaultFilename: format + "-results.csv",
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export rankings: %v", err)
+	}
+	defer f.Close()
+	if err := writeRankings(f, format, entries); err != nil {
+		return nil, fmt.Errorf("failed to export rankings: %v", err)
+	}
+	resultsLog.Info("Rankings exported", "format", format, "path", path, "rows", len(entries), "issues", len(issues))
+	return &RankingReport{Format: format, Rows: len(entries), Issues: issues, Path: path}, nil
+}
+
+// registerRankingRoutes adds the rankings submission endpoints to the Fiber
+// server: /export/rankings/<format> for the CSV and .../report for the validation
+// report. ?session= exports one session.
+func registerRankingRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/export/rankings/:format", func(c *fiber.Ctx) error {
+		format := c.Params("format")
+		entries, _, err := app.rankingEntries(format, c.Query("session"))
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-results.csv"`, format))
+		return writeRankings(c.Response().BodyWriter(), format, entries)
+	})
+	fiberApp.Get("/export/rankings/:format/report", func(c *fiber.Ctx) error {
+		report, err := app.CheckRankings(c.Params("format"), c.Query("session"))
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(report)
+	})
+}
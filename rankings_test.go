@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRankingEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		distance int
+		ok       bool
+	}{
+		{"U17 Men 100m", "100", 100, true},
+		{"Women 100 m", "100", 100, true},
+		{"Senior Women 400 m Hurdles", "400H", 400, true},
+		{"Men 110mH Final", "110H", 110, true},
+		{"U20 Women 3000m S/C", "3000SC", 3000, true},
+		{"Men 4 x 100m Relay", "4x100", 400, true},
+		{"V40 Men Mile", "Mile", 1609, true},
+		{"Race 3 U15 Girls 1500m", "1500", 1500, true},
+		{"Women 5000m Walk", "5000W", 5000, true},
+		{"Long Jump", "", 0, false},
+		{"Heat 12", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, distance, ok := rankingEvent(tt.name)
+			if code != tt.code || distance != tt.distance || ok != tt.ok {
+				t.Errorf("rankingEvent(%q) = %q, %d, %v, want %q, %d, %v", tt.name, code, distance, ok, tt.code, tt.distance, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRankingCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		gender   string
+		ageGroup string
+	}{
+		{"Women 100 m", "W", ""},
+		{"U15 Girls 1500 m", "W", "U15"},
+		{"Senior Women 400 m Hurdles", "W", "SEN"},
+		{"U17 Men 100m", "M", "U17"},
+		{"Men 200 m", "M", ""},
+		{"U15G 75m Hurdles", "W", "U15"},
+		{"SM 100m", "M", "SEN"},
+		{"SW 100 m", "W", "SEN"},
+		{"V40 Men 800m", "M", "V40"},
+		{"W35 200m", "W", "V35"},
+		{"M 100m", "M", ""},
+		{"Mixed 4x400m Relay", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gender, ageGroup := rankingCategory(tt.name)
+			if gender != tt.gender || ageGroup != tt.ageGroup {
+				t.Errorf("rankingCategory(%q) = %q, %q, want %q, %q", tt.name, gender, ageGroup, tt.gender, tt.ageGroup)
+			}
+		})
+	}
+}
+
+// TestWriteRankings compares each format with a golden file in testdata, so any
+// change to the column layout of an upload is deliberate. Run with -update to
+// rewrite them.
+func TestWriteRankings(t *testing.T) {
+	date := time.Date(2026, 6, 13, 10, 0, 0, 0, time.Local)
+	entries := []rankingEntry{
+		{Event: "100", Gender: "W", AgeGroup: "U17", Round: "1", Heat: "2", Place: "1", Bib: "101", FirstName: "Alice", LastName: "Smith", Club: "Kingston AC", Performance: "12.01", Wind: "+1.2", Date: date},
+		{Event: "100", Gender: "W", AgeGroup: "U17", Round: "1", Heat: "2", Bib: "102", FirstName: "Bella", LastName: "Jones", Club: "Sutton, Surrey", Wind: "+1.2", Status: "DQ", Date: date},
+		{Event: "100", Gender: "W", AgeGroup: "U17", Round: "1", Heat: "2", Bib: "103", FirstName: "Cara", LastName: "Brown", Club: "Herne Hill", Wind: "+1.2", Status: "DNS", Date: date},
+		{Event: "10000", Gender: "M", AgeGroup: "SEN", Place: "1", Bib: "7", FirstName: "Dan", LastName: "O'Neill", Club: "Belgrave", Performance: "29:41", Date: date},
+	}
+	for _, format := range []string{rankingPowerOf10, rankingOpenTrack} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeRankings(&out, format, entries); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", "rankings-"+format+".csv")
+			if *update {
+				if err := os.WriteFile(golden, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Errorf("writeRankings(%s) =\n%s\nwant\n%s", format, out.Bytes(), want)
+			}
+		})
+	}
+}
+
+func TestRankingEntriesDNS(t *testing.T) {
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "U17 Women 100 m",
+		"1,101,4,Smith,Alice,Kingston,12.01",
+		"2,102,5,Jones,Bella,Sutton,12.30",
+		"DNS,103,6,Brown,Cara,Herne Hill,")
+	res := "U17 Women 200 m.res\t+0.5\n" +
+		"Place\tLane\tTime\tID\tName\tInfo\n" +
+		"1\t3\t25.10\t201\tDina Evans\tWoking\n" +
+		"DNS\t4\t\t202\tEve Fox\tWoking\n" +
+		"DNS\t5\t\t203\t\t\n"
+	if err := os.WriteFile(filepath.Join(dir, "b.res"), []byte(res), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, dir, false)
+	app.overrides = []ResultOverride{
+		{FileName: "a.lif", Bib: "102", Status: "DNS"},
+		{FileName: "a.lif", Bib: "103", FirstName: "Carla"},
+	}
+
+	entries, issues, err := app.rankingEntries(rankingPowerOf10, sessionAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type row struct{ event, place, name, status string }
+	var got []row
+	for _, e := range entries {
+		got = append(got, row{e.Event, e.Place, e.FirstName + " " + e.LastName, e.Status})
+	}
+	want := []row{
+		{"100", "1", "Alice Smith", ""},
+		{"100", "", "Carla Brown", "DNS"},
+		{"100", "", "Bella Jones", "DNS"},
+		{"200", "1", "Dina Evans", ""},
+		{"200", "", "Eve Fox", "DNS"},
+	}
+	if len(got) == len(want) && got[0].event != "100" {
+		// Both files are saved within the same second, so either may come first.
+		got = append(got[2:], got[:2]...)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+	// The .res DNS row without a name can't be exported, but is reported.
+	if len(issues) != 1 || issues[0].File != "b.res" || issues[0].Problem != "missing first or last name" {
+		t.Errorf("issues = %+v, want the unnamed DNS row of b.res", issues)
+	}
+}
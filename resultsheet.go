@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/gofiber/fiber/v2"
+)
+
+// resultSheetLogos are the file names a meet logo is looked for under in the
+// results directory, to print at the top of each result sheet.
+var resultSheetLogos = []string{"logo.png", "logo.jpg", "logo.jpeg"}
+
+// resultSheetColumn is a column of the results table on a result sheet.
+type resultSheetColumn struct {
+	title string
+	width float64 // mm
+	align string  // 'L' or 'R'
+}
+
+var resultSheetColumns = []resultSheetColumn{
+	{"Place", 16, "L"},
+	{"Bib", 16, "L"},
+	{"Name", 66, "L"},
+	{"Club", 62, "L"},
+	{"Time", 20, "R"},
+}
+
+// findResultSheetLogo returns the meet logo in dir, or an empty string if it has none.
+func findResultSheetLogo(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for _, name := range resultSheetLogos {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// eventHeading returns the round, heat, wind and time saved of an event, for under its title.
+func eventHeading(data *LifData) string {
+	var parts []string
+	if data.Round != "" && data.Round != "0" {
+		parts = append(parts, "Round "+data.Round)
+	}
+	if data.Heat != "" && data.Heat != "0" {
+		parts = append(parts, "Heat "+data.Heat)
+	}
+	if data.Wind != "" {
+		parts = append(parts, "Wind "+data.Wind)
+	}
+	parts = append(parts, "Saved "+time.Unix(data.ModifiedTime, 0).Format("Mon 2 Jan 2006 15:04"))
+	if data.Amended {
+		parts = append(parts, "Amended")
+	}
+	return strings.Join(parts, "  ·  ")
+}
+
+// buildResultSheets renders results as A4 result sheets, one event per page (or
+// more if an event doesn't fit), with the meet in the header of every page.
+func buildResultSheets(results []*LifData, meet Meet, acronyms map[string]string, logo string, now time.Time) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreator("PolyField Track "+appVersion, true)
+	pdf.SetCreationDate(now)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AliasNbPages("")
+	// The core fonts are in cp1252, which covers the accents in most athlete names.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 30
+
+	title := meet.Name
+	if title == "" {
+		title = "Results"
+	}
+	pdf.SetTitle(title, true)
+	var subtitle []string
+	if meet.Venue != "" {
+		subtitle = append(subtitle, meet.Venue)
+	}
+	if meet.StartDate != "" {
+		dates := meet.StartDate
+		if meet.EndDate != "" && meet.EndDate != meet.StartDate {
+			dates += " to " + meet.EndDate
+		}
+		subtitle = append(subtitle, dates)
+	}
+	logoWidth := 0.0
+	if logo != "" {
+		info := pdf.RegisterImageOptions(logo, fpdf.ImageOptions{ReadDpi: true})
+		if pdf.Err() {
+			// A logo that can't be read shouldn't stop the results being printed.
+			resultsLog.Warn("Result sheet logo not used", "path", logo, "error", pdf.Error())
+			pdf.ClearError()
+			logo = ""
+		} else {
+			logoWidth = 16 * info.Width() / info.Height()
+		}
+	}
+
+	var current *LifData
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(21, 101, 192)
+		pdf.SetTextColor(255, 255, 255)
+		for _, column := range resultSheetColumns {
+			pdf.CellFormat(column.width, 7, column.title, "", 0, column.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetHeaderFuncMode(func() {
+		x := 15.0
+		if logo != "" {
+			pdf.ImageOptions(logo, 15, 10, logoWidth, 16, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+			x += logoWidth + 4
+		}
+		pdf.SetXY(x, 11)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 7, tr(title), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 5, tr(strings.Join(subtitle, ", ")), "", 1, "L", false, 0, "")
+		pdf.SetDrawColor(10, 22, 40)
+		pdf.Line(15, 28, pageWidth-15, 28)
+		pdf.SetXY(15, 32)
+		if current != nil {
+			// An event continued from the previous page gets its title and columns again.
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(0, 7, tr(current.EventName+" (continued)"), "", 1, "L", false, 0, "")
+			tableHeader()
+		}
+	}, true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-13)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(contentWidth/2, 5, tr("Printed "+now.Format("Mon 2 Jan 2006 15:04")+" by PolyField Track"), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	// fit shortens text to fit a column, leaving a little padding.
+	fit := func(text string, width float64) string {
+		text = tr(text)
+		for text != "" && pdf.GetStringWidth(text) > width-2 {
+			text = text[:len(text)-1]
+		}
+		return text
+	}
+
+	if len(results) == 0 {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 8, "No results.", "", 1, "L", false, 0, "")
+	}
+	for _, data := range results {
+		// Only the later pages of an event repeat its title in the header.
+		current = nil
+		pdf.AddPage()
+		current = data
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.MultiCell(0, 8, tr(data.EventName), "", "L", false)
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetTextColor(80, 80, 80)
+		pdf.CellFormat(0, 6, tr(eventHeading(data)), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(3)
+		tableHeader()
+		for i, c := range data.Competitors {
+			club := strings.TrimSpace(c.Affiliation)
+			pdf.SetFont("Helvetica", "", 10)
+			if pdf.GetStringWidth(tr(club)) > resultSheetColumns[3].width-2 {
+				// Use the club's acronym rather than cut its name short.
+				if acronym := clubAcronym(club, acronyms); acronym != "" {
+					club = acronym
+				}
+			}
+			values := []string{c.Place, c.ID, strings.TrimSpace(c.FirstName + " " + c.LastName), club, c.Time}
+			pdf.SetFillColor(240, 244, 248)
+			for j, column := range resultSheetColumns {
+				pdf.CellFormat(column.width, 6.5, fit(values[j], column.width), "B", 0, column.align, i%2 == 1, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+	current = nil
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to create PDF: %v", err)
+	}
+	return out.Bytes(), nil
+}
+
+// resultSheetResults returns the results for a result sheet: the result in file,
+// or if file is empty, the results of a session ('all', 'today', 'current' or a
+// session ID), oldest first. It also returns a name for the sheet's file.
+func (a *App) resultSheetResults(file string, session string) ([]*LifData, string, error) {
+	if file != "" {
+		results, err := a.GetAllLIFData()
+		if err != nil {
+			return nil, "", err
+		}
+		for _, data := range results {
+			if data.FileName == file {
+				return []*LifData{data}, strings.TrimSuffix(file, filepath.Ext(file)), nil
+			}
+		}
+		return nil, "", notFoundError(fmt.Sprintf("no result %q", file))
+	}
+	results, err := a.GetSessionResults(session)
+	if err != nil {
+		return nil, "", err
+	}
+	sorted := append([]*LifData(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ModifiedTime < sorted[j].ModifiedTime })
+	name := session
+	if name == "" {
+		name = a.GetMeet().DisplayFilter
+	}
+	return sorted, "session-" + name, nil
+}
+
+// renderResultSheet renders the result sheet of a result file or a session.
+func (a *App) renderResultSheet(file string, session string) ([]byte, string, error) {
+	results, name, err := a.resultSheetResults(file, session)
+	if err != nil {
+		return nil, "", err
+	}
+	dir := a.GetMonitoredDirectory()
+	sheet, err := buildResultSheets(results, a.GetMeet(), a.clubAcronyms(), findResultSheetLogo(dir), time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	return sheet, name, nil
+}
+
+// saveResultSheet renders the result sheet of a result file or a session and
+// saves it in the results directory, returning its path.
+func (a *App) saveResultSheet(file string, session string) (string, error) {
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return "", fmt.Errorf("no directory selected")
+	}
+	sheet, name, err := a.renderResultSheet(file, session)
+	if err != nil {
+		return "", err
+	}
+	// e.g. PolyField-Track_130626_100m-final.pdf
+	filename := fmt.Sprintf("PolyField-Track_%s_%s.pdf", time.Now().Format("020106"), uniqueSlug(name, map[string]bool{}))
+	fullPath := filepath.Join(dir, filename)
+	if err := os.WriteFile(fullPath, sheet, 0644); err != nil {
+		return "", fmt.Errorf("failed to save result sheet: %v", err)
+	}
+	resultsLog.Info("Result sheet saved", "path", fullPath)
+	return fullPath, nil
+}
+
+// SaveResultSheet saves a PDF result sheet of one result file in the results
+// directory and returns its path.
+func (a *App) SaveResultSheet(fileName string) (string, error) {
+	if fileName == "" {
+		return "", fmt.Errorf("no result given")
+	}
+	return a.saveResultSheet(fileName, "")
+}
+
+// SaveSessionResultSheets saves PDF result sheets of every event in a session
+// ('all', 'today', 'current', a session ID, or empty for the meet's display
+// filter) in the results directory and returns the path.
+func (a *App) SaveSessionResultSheets(session string) (string, error) {
+	return a.saveResultSheet("", session)
+}
+
+// registerResultSheetRoutes adds the PDF result sheet endpoints to the Fiber server.
+func registerResultSheetRoutes(fiberApp *fiber.App, app *App) {
+	// GET returns the sheet to print; ?file= for one result, otherwise ?session=.
+	fiberApp.Get("/result-sheet", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		sheet, name, err := app.renderResultSheet(c.Query("file"), c.Query("session"))
+		var notFound notFoundError
+		if errors.As(err, &notFound) {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		c.Set("Content-Type", "application/pdf")
+		c.Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, uniqueSlug(name, map[string]bool{})))
+		return c.Send(sheet)
+	})
+	// POST saves the sheet in the results directory.
+	fiberApp.Post("/result-sheet", app.requireRole(roleOperator), func(c *fiber.Ctx) error {
+		var req struct {
+			File    string `json:"file"`
+			Session string `json:"session"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		path, err := app.saveResultSheet(req.File, req.Session)
+		if err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true, "path": path})
+	})
+}
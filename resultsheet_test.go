@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestResultSheetRoute(t *testing.T) {
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.01")
+	app := newTestApp(t, dir, false)
+	fiberApp := fiber.New()
+	registerResultSheetRoutes(fiberApp, app)
+
+	tests := []struct {
+		name  string
+		query string
+		dir   string
+		want  int
+	}{
+		{"result", "?file=a.lif", dir, 200},
+		{"session", "?session=all", dir, 200},
+		{"unknown result", "?file=b.lif", dir, 404},
+		{"unknown session", "?session=2020-01-01T09:00", dir, 404},
+		{"no directory", "?file=a.lif", "", 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.monitoredDir = tt.dir
+			req := httptest.NewRequest("GET", "/result-sheet"+tt.query, nil)
+			req.Header.Set("X-Operator-Token", app.GetOperatorToken())
+			resp, err := fiberApp.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultListenAddress listens on all interfaces so displays on the LAN can connect.
+	defaultListenAddress = "0.0.0.0"
+	// defaultPort is the HTTP port used when none has been configured.
+	defaultPort = 3000
+	// portFallbackAttempts is how many ports, starting at the configured one, are tried
+	// before giving up when a port is already in use.
+	portFallbackAttempts = 10
+	// serverShutdownTimeout is how long in-flight requests get to finish when the server restarts.
+	serverShutdownTimeout = 5 * time.Second
+)
+
+// ServerSettings is where the web server listens.
+type ServerSettings struct {
+	ListenAddress   string `json:"listenAddress"`
+	Port            int    `json:"port"`
+	ActivePort      int    `json:"activePort"`      // Port in use, which differs from Port if it was taken
+	ActiveHTTPSPort int    `json:"activeHttpsPort"` // 0 when HTTPS isn't running
+}
+
+// listenWithFallback listens on address:port, moving on to the following ports if
+// it is in use. It returns the listener and the port it got.
+func listenWithFallback(address string, port int) (net.Listener, int, error) {
+	var lastErr error
+	for attempt := 0; attempt < portFallbackAttempts && port+attempt <= 65535; attempt++ {
+		candidate := port + attempt
+		ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(candidate)))
+		if err == nil {
+			if attempt > 0 {
+				serverLog.Warn("Port in use, using the next free port", "port", port, "using", candidate)
+			}
+			return ln, candidate, nil
+		}
+		lastErr = err
+	}
+	return nil, 0, fmt.Errorf("no free port from %d to %d: %v", port, port+portFallbackAttempts-1, lastErr)
+}
+
+// serverAddress returns the configured listen address and port, with defaults filled in.
+func (a *App) serverAddress() (string, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	address, port := a.listenAddress, a.port
+	if address == "" {
+		address = defaultListenAddress
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	return address, port
+}
+
+// localServerURL returns the URL this machine can reach the web server on.
+func (a *App) localServerURL() string {
+	address, _ := a.serverAddress()
+	a.mu.Lock()
+	port := a.activePort
+	a.mu.Unlock()
+	if ip := net.ParseIP(address); ip == nil || ip.IsUnspecified() {
+		address = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(address, strconv.Itoa(port))
+}
+
+// GetServerURL returns the URL the desktop app uses to reach its own web server.
+func (a *App) GetServerURL() string {
+	return a.localServerURL()
+}
+
+// GetServerSettings returns the configured and active listen address and ports.
+func (a *App) GetServerSettings() ServerSettings {
+	address, port := a.serverAddress()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return ServerSettings{
+		ListenAddress:   address,
+		Port:            port,
+		ActivePort:      a.activePort,
+		ActiveHTTPSPort: a.activeHTTPSPort,
+	}
+}
+
+// normaliseServerAddress validates a listen address and port. An empty address
+// listens on all interfaces.
+func normaliseServerAddress(address string, port int) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		address = defaultListenAddress
+	}
+	if net.ParseIP(address) == nil {
+		return "", fmt.Errorf("invalid listen address %q, expected an IP address such as 0.0.0.0", address)
+	}
+	if port < 1 || port > 65535 {
+		return "", fmt.Errorf("invalid port %d", port)
+	}
+	return address, nil
+}
+
+// SetServerSettings changes where the web server listens and restarts it. An empty
+// address listens on all interfaces.
+func (a *App) SetServerSettings(address string, port int) error {
+	address, err := normaliseServerAddress(address, port)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.listenAddress = address
+	a.port = port
+	a.mu.Unlock()
+	a.scheduleSave()
+	return a.restartServer()
+}
+
+// restartServer stops the web server, HTTPS and mDNS and starts them again with
+// the current settings. Displays reconnect on their next poll.
+func (a *App) restartServer() error {
+	a.serverMu.Lock()
+	defer a.serverMu.Unlock()
+	a.mu.Lock()
+	old := a.server
+	a.server = nil
+	a.httpsListener = nil
+	a.activeHTTPSPort = 0
+	a.mu.Unlock()
+	if old != nil {
+		serverLog.Info("Restarting web server")
+		if err := old.ShutdownWithTimeout(serverShutdownTimeout); err != nil {
+			serverLog.Error("Failed to stop web server", "error", err)
+		}
+	}
+	a.stopMDNS()
+	if err := StartFiberServer(a); err != nil {
+		return err
+	}
+	go a.startMDNS()
+	return nil
+}
+
+// shutdown stops the web server and mDNS, stops watching the results directory and
+// writes any settings change that is still waiting to be saved.
+func (a *App) shutdown() {
+	a.serverMu.Lock()
+	a.mu.Lock()
+	old := a.server
+	a.server = nil
+	a.httpsListener = nil
+	watcher := a.watcher
+	a.watcher = nil
+	a.mu.Unlock()
+	if old != nil {
+		if err := old.ShutdownWithTimeout(serverShutdownTimeout); err != nil {
+			serverLog.Error("Failed to stop web server", "error", err)
+		}
+	}
+	a.serverMu.Unlock()
+	a.stopMDNS()
+	if watcher != nil {
+		watcher.Close()
+	}
+	a.CloseArchive()
+	a.saveMu.Lock()
+	pending := a.saveTimer != nil && a.saveTimer.Stop()
+	a.saveMu.Unlock()
+	if pending {
+		a.saveSettings()
+	}
+	serverLog.Info("Shut down")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"testing"
+)
+
+// freePorts returns the first of n consecutive ports that are free on 127.0.0.1.
+func freePorts(t *testing.T, n int) int {
+	t.Helper()
+	for base := 41000; base < 60000; base += 100 {
+		free := true
+		for p := base; p < base+n; p++ {
+			ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(p)))
+			if err != nil {
+				free = false
+				break
+			}
+			ln.Close()
+		}
+		if free {
+			return base
+		}
+	}
+	t.Fatal("no free ports")
+	return 0
+}
+
+func TestListenWithFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		taken    int // how many ports from the start are already in use
+		wantPort int // offset of the port expected, or -1 for an error
+	}{
+		{"port free", 0, 0},
+		{"port taken", 1, 1},
+		{"several taken", 3, 3},
+		{"all taken", portFallbackAttempts, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := freePorts(t, portFallbackAttempts+1)
+			for p := base; p < base+tt.taken; p++ {
+				ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(p)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer ln.Close()
+			}
+			ln, port, err := listenWithFallback("127.0.0.1", base)
+			if tt.wantPort < 0 {
+				if err == nil {
+					ln.Close()
+					t.Fatalf("listenWithFallback() got port %d, want an error", port)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+			if port != base+tt.wantPort {
+				t.Errorf("listenWithFallback() port = %d, want %d", port, base+tt.wantPort)
+			}
+			if got := ln.Addr().(*net.TCPAddr).Port; got != port {
+				t.Errorf("listener is on port %d, reported %d", got, port)
+			}
+		})
+	}
+}
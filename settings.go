@@ -0,0 +1,385 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// meetSettingsFile is the optional per-meet settings file kept in the results directory.
+// When present it takes precedence over the app config for that directory.
+const meetSettingsFile = "polyfield-meet.json"
+
+// Settings is the state restored when the app starts.
+type Settings struct {
+	MonitoredDir    string                   `json:"monitoredDir"`
+	DisplayState    *DisplayState            `json:"displayState"` // Default channel
+	Channels        map[string]*DisplayState `json:"channels"`     // Named channels other than the default
+	HoldForApproval bool                     `json:"holdForApproval"`
+	Playlists       []Playlist               `json:"playlists"`
+	HTTPS           bool                     `json:"https"`         // Only read from the app settings, not per-meet files
+	ListenAddress   string                   `json:"listenAddress"` // Only read from the app settings
+	Port            int                      `json:"port"`          // Only read from the app settings
+	InstanceName    string                   `json:"instanceName"`  // Only read from the app settings
+	MDNSHostname    string                   `json:"mdnsHostname"`  // Only read from the app settings
+	Replication     ReplicationSettings      `json:"replication"`   // Only read from the app settings
+	LogLevel        string                   `json:"logLevel"`      // Only read from the app settings
+	Meet            *Meet                    `json:"meet,omitempty"`
+}
+
+// settingsPath returns the location of the app settings file in the user config directory.
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "PolyField-Track", "settings.json"), nil
+}
+
+// settingsFilePath returns the settings file in use: the file given to headless
+// mode with -config, or the app settings file.
+func (a *App) settingsFilePath() (string, error) {
+	if a.settingsFile != "" {
+		return a.settingsFile, nil
+	}
+	return settingsPath()
+}
+
+// readSettings reads a settings file. A missing file returns nil settings and no error.
+func readSettings(path string) (*Settings, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings Settings
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return &settings, nil
+}
+
+// writeSettings writes a settings file, creating its directory if needed.
+func writeSettings(path string, settings *Settings) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+	raw, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+	// Write to a temporary file first so a crash mid-write can't corrupt the settings.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// currentSettings snapshots the state that is persisted.
+func (a *App) currentSettings() *Settings {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	settings := &Settings{
+		MonitoredDir:    a.monitoredDir,
+		Channels:        make(map[string]*DisplayState),
+		HoldForApproval: a.holdForApproval,
+		HTTPS:           a.httpsEnabled,
+		ListenAddress:   a.listenAddress,
+		Port:            a.port,
+		InstanceName:    a.instanceName,
+		MDNSHostname:    a.mdnsHostname,
+		Replication:     a.replication,
+		LogLevel:        logLevelName(),
+		Meet:            a.meet,
+	}
+	for _, playlist := range a.playlists {
+		settings.Playlists = append(settings.Playlists, *playlist)
+	}
+	for name, channel := range a.channels {
+		state := *channel
+		if name == defaultChannel {
+			settings.DisplayState = &state
+		} else {
+			settings.Channels[name] = &state
+		}
+	}
+	return settings
+}
+
+// applySettings restores the persisted display state and options.
+func (a *App) applySettings(settings *Settings) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if settings.DisplayState != nil {
+		state := *settings.DisplayState
+		a.channels = map[string]*DisplayState{defaultChannel: &state}
+	}
+	for name, channel := range settings.Channels {
+		if name != defaultChannel && channel != nil {
+			state := *channel
+			a.channels[name] = &state
+		}
+	}
+	a.holdForApproval = settings.HoldForApproval
+	if settings.Playlists != nil {
+		a.playlists = make(map[string]*Playlist, len(settings.Playlists))
+		for i := range settings.Playlists {
+			playlist := settings.Playlists[i]
+			a.playlists[playlist.Name] = &playlist
+		}
+	}
+	if settings.Meet != nil {
+		a.meet = settings.Meet
+	}
+}
+
+// meetSettings returns the settings written to a per-meet file. The results folder
+// is often shared, so the replication key is left out.
+func meetSettings(settings *Settings) *Settings {
+	meet := *settings
+	meet.Replication = ReplicationSettings{}
+	return &meet
+}
+
+// saveSettings persists the current settings to the app config and, if it exists,
+// to the per-meet file in the monitored directory.
+func (a *App) saveSettings() {
+	settings := a.currentSettings()
+	if path, err := a.settingsFilePath(); err != nil {
+		settingsLog.Error("Failed to locate settings file", "error", err)
+	} else if err := writeSettings(path, settings); err != nil {
+		settingsLog.Error("Failed to save settings", "error", err)
+	}
+	if settings.MonitoredDir == "" {
+		return
+	}
+	meetPath := filepath.Join(settings.MonitoredDir, meetSettingsFile)
+	if _, err := os.Stat(meetPath); err == nil {
+		if err := writeSettings(meetPath, meetSettings(settings)); err != nil {
+			settingsLog.Error("Failed to save meet settings", "file", meetSettingsFile, "error", err)
+		}
+	}
+}
+
+// scheduleSave saves the settings shortly after the last change, so that a burst
+// of updates (e.g. a POST to /display-state) results in a single write.
+// It uses its own lock so it can be called while a.mu is held.
+func (a *App) scheduleSave() {
+	a.saveMu.Lock()
+	defer a.saveMu.Unlock()
+	if a.saveTimer != nil {
+		a.saveTimer.Stop()
+	}
+	a.saveTimer = time.AfterFunc(500*time.Millisecond, a.saveSettings)
+}
+
+// loadSettings restores the settings saved by a previous run and resumes
+// monitoring the last directory, if it still exists.
+func (a *App) loadSettings() {
+	path, err := a.settingsFilePath()
+	if err != nil {
+		settingsLog.Error("Failed to locate settings file", "error", err)
+		return
+	}
+	settings, err := readSettings(path)
+	if err != nil {
+		settingsLog.Error("Failed to load settings", "error", err)
+		return
+	}
+	if settings == nil {
+		return
+	}
+	a.applySettings(settings)
+	a.mu.Lock()
+	a.httpsEnabled = settings.HTTPS
+	a.listenAddress = settings.ListenAddress
+	a.port = settings.Port
+	a.instanceName = settings.InstanceName
+	a.mdnsHostname = settings.MDNSHostname
+	a.replication = settings.Replication
+	a.mu.Unlock()
+	if settings.LogLevel != "" {
+		if err := setLogLevel(settings.LogLevel); err != nil {
+			settingsLog.Warn("Ignoring saved log level", "error", err)
+		}
+	}
+	a.migrateChannelImages()
+	settingsLog.Info("Settings restored", "path", path)
+
+	if settings.MonitoredDir == "" {
+		return
+	}
+	if info, err := os.Stat(settings.MonitoredDir); err != nil || !info.IsDir() {
+		settingsLog.Warn("Previously monitored directory is no longer available", "dir", settings.MonitoredDir)
+		return
+	}
+	a.openDirectory(settings.MonitoredDir)
+}
+
+// loadMeetSettings applies the per-meet settings file in dir, if there is one.
+func (a *App) loadMeetSettings(dir string) {
+	settings, err := readSettings(filepath.Join(dir, meetSettingsFile))
+	if err != nil {
+		settingsLog.Error("Failed to load meet settings", "file", meetSettingsFile, "error", err)
+		return
+	}
+	if settings == nil {
+		return
+	}
+	a.applySettings(settings)
+	a.migrateChannelImages()
+	settingsLog.Info("Meet settings restored", "path", filepath.Join(dir, meetSettingsFile))
+}
+
+// GetMonitoredDirectory returns the results directory currently being monitored.
+func (a *App) GetMonitoredDirectory() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.monitoredDir
+}
+
+// SetMonitoredDirectory starts monitoring dir, as ChooseDirectory does without the
+// dialog. It is used by the REST API and headless mode.
+func (a *App) SetMonitoredDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open directory: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	settingsLog.Info("Directory selected", "dir", dir)
+	a.openDirectory(dir)
+	a.scheduleSave()
+	return nil
+}
+
+// GetMeetSettingsEnabled reports whether the monitored directory has a per-meet settings file.
+func (a *App) GetMeetSettingsEnabled() bool {
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, meetSettingsFile))
+	return err == nil
+}
+
+// SetMeetSettingsEnabled creates or removes the per-meet settings file in the monitored directory.
+func (a *App) SetMeetSettingsEnabled(enabled bool) error {
+	dir := a.GetMonitoredDirectory()
+	if dir == "" {
+		return fmt.Errorf("no directory selected")
+	}
+	meetPath := filepath.Join(dir, meetSettingsFile)
+	if !enabled {
+		if err := os.Remove(meetPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", meetSettingsFile, err)
+		}
+		return nil
+	}
+	return writeSettings(meetPath, meetSettings(a.currentSettings()))
+}
+
+// registerSettingsRoutes adds the settings endpoints to the Fiber server, so that
+// everything the desktop app can change is also available without it.
+func registerSettingsRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/settings", app.requireRole(roleViewer), func(c *fiber.Ctx) error {
+		replication := app.GetReplicationSettings()
+		replication.Key = ""
+		return c.JSON(map[string]interface{}{
+			"monitoredDir":    app.GetMonitoredDirectory(),
+			"meetSettings":    app.GetMeetSettingsEnabled(),
+			"holdForApproval": app.GetHoldForApproval(),
+			"https":           app.GetHTTPSEnabled(),
+			"server":          app.GetServerSettings(),
+			"mdns":            app.GetMDNSSettings(),
+			"replication":     replication,
+		})
+	})
+	fiberApp.Post("/settings/directory", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Path string `json:"path"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetMonitoredDirectory(req.Path); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/settings/meet-file", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetMeetSettingsEnabled(req.Enabled); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/settings/https", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetHTTPSEnabled(req.Enabled); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/settings/server", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req struct {
+			ListenAddress string `json:"listenAddress"`
+			Port          int    `json:"port"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if _, err := normaliseServerAddress(req.ListenAddress, req.Port); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		// Restart after this response has been sent; the restart waits for it to finish.
+		go func() {
+			if err := app.SetServerSettings(req.ListenAddress, req.Port); err != nil {
+				settingsLog.Error("Failed to change server settings", "error", err)
+			}
+		}()
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/settings/mdns", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req MDNSSettings
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetMDNSSettings(req.InstanceName, req.Hostname); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+	fiberApp.Post("/settings/replication", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		var req ReplicationSettings
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		if err := app.SetReplicationSettings(req); err != nil {
+			return c.Status(400).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(map[string]interface{}{"success": true})
+	})
+}
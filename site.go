@@ -0,0 +1,546 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// sitePage is a file of the static results site, with its path relative to the site root.
+type sitePage struct {
+	Name    string
+	Content []byte
+}
+
+// siteEvent is an event page of the results site.
+type siteEvent struct {
+	Page    string // e.g. 'events/100m-final.html'
+	Data    *LifData
+	Saved   time.Time
+	Session string
+	Rows    []siteRow
+}
+
+// siteRow is a competitor in an event, linked to their athlete page.
+type siteRow struct {
+	Competitor
+	Name        string
+	Club        string // Acronym if the club has one, otherwise the full name
+	AthletePage string
+}
+
+// siteSession is a session on the index page, with its events oldest first.
+type siteSession struct {
+	Name   string
+	Events []*siteEventGroup
+}
+
+// siteEventGroup is an event on the index page with its rounds and heats, oldest first.
+type siteEventGroup struct {
+	Name   string
+	key    string
+	Events []*siteEvent
+}
+
+// siteAthlete is an athlete page of the results site.
+type siteAthlete struct {
+	Page    string
+	Name    string
+	SortKey string
+	Club    string
+	Acronym string
+	Results []siteAthleteResult
+}
+
+// siteAthleteResult is one of an athlete's results.
+type siteAthleteResult struct {
+	Event *siteEvent
+	Place string
+	Time  string
+}
+
+var (
+	clubAcronymsOnce  sync.Once
+	clubAcronymsLower map[string]string // defaultClubAcronyms keyed by lowercased name
+)
+
+// clubAcronym returns the acronym of a club, looking in the meet's club list
+// (keyed by lowercased name) before the built-in list, or an empty string if it
+// has none. It matches shortenClub in the frontend.
+func clubAcronym(affiliation string, custom map[string]string) string {
+	lower := strings.ToLower(strings.TrimSpace(affiliation))
+	if lower == "" {
+		return ""
+	}
+	if acronym, ok := custom[lower]; ok {
+		return acronym
+	}
+	clubAcronymsOnce.Do(func() {
+		clubAcronymsLower = make(map[string]string, len(defaultClubAcronyms))
+		for name, acronym := range defaultClubAcronyms {
+			clubAcronymsLower[strings.ToLower(name)] = acronym
+		}
+	})
+	return clubAcronymsLower[lower]
+}
+
+// clubAcronyms returns the meet's own club acronyms, from the open archive if there is one.
+func (a *App) clubAcronyms() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archive != nil {
+		return a.archive.acronyms
+	}
+	return a.customClubAcronyms
+}
+
+var (
+	slugUnsafe   = regexp.MustCompile(`[^a-z0-9]+`)
+	roundPattern = regexp.MustCompile(`(?i)[\s,-]*\b(heat|round|semi[ -]?finals?|semis?|finals?|qualifying|prelims?)\b\s*(\d+|[a-z])?\b`)
+)
+
+// eventGroupName returns the name of an event without its round or heat, e.g.
+// 'U17 Women 100m' for 'U17 Women 100m Heat 2', so rounds and heats of the same
+// event can be listed together.
+func eventGroupName(name string) string {
+	group := strings.Join(strings.Fields(roundPattern.ReplaceAllString(name, " ")), " ")
+	if group == "" {
+		return strings.TrimSpace(name)
+	}
+	return group
+}
+
+// uniqueSlug returns a file name made from name that isn't in used yet, and marks it used.
+func uniqueSlug(name string, used map[string]bool) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "page"
+	}
+	unique := slug
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", slug, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// buildSite renders results as a static HTML site: an index of the events by
+// session, with the rounds and heats of each event together, a page per event,
+// an index of athletes and a page per athlete.
+func buildSite(results []*LifData, meet Meet, acronyms map[string]string, now time.Time) ([]sitePage, error) {
+	sorted := append([]*LifData(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ModifiedTime < sorted[j].ModifiedTime })
+	sessions := meet.sessions(sorted, now)
+
+	eventSlugs := map[string]bool{}
+	athleteSlugs := map[string]bool{}
+	athletes := map[string]*siteAthlete{}
+	indexSessions := make([]*siteSession, len(sessions))
+	for i, session := range sessions {
+		indexSessions[i] = &siteSession{Name: session.Name}
+	}
+	var events []*siteEvent
+	for _, data := range sorted {
+		name := strings.TrimSuffix(data.FileName, filepath.Ext(data.FileName))
+		event := &siteEvent{
+			Page:  "events/" + uniqueSlug(name, eventSlugs) + ".html",
+			Data:  data,
+			Saved: time.Unix(data.ModifiedTime, 0),
+		}
+		if len(sessions) > 0 {
+			session := indexSessions[sessionIndex(sessions, data.ModifiedTime)]
+			event.Session = session.Name
+			groupName := eventGroupName(data.EventName)
+			var group *siteEventGroup
+			for _, g := range session.Events {
+				if g.key == strings.ToLower(groupName) {
+					group = g
+				}
+			}
+			if group == nil {
+				group = &siteEventGroup{Name: groupName, key: strings.ToLower(groupName)}
+				session.Events = append(session.Events, group)
+			}
+			group.Events = append(group.Events, event)
+		}
+		for _, c := range data.Competitors {
+			fullName := strings.TrimSpace(c.FirstName + " " + c.LastName)
+			club := strings.TrimSpace(c.Affiliation)
+			acronym := clubAcronym(club, acronyms)
+			row := siteRow{Competitor: c, Name: fullName, Club: club}
+			if acronym != "" {
+				row.Club = acronym
+			}
+			if fullName != "" {
+				// The same name at the same club is taken to be the same athlete.
+				key := strings.ToLower(fullName + "\x00" + club)
+				athlete, ok := athletes[key]
+				if !ok {
+					athlete = &siteAthlete{
+						Page:    "athletes/" + uniqueSlug(fullName+" "+club, athleteSlugs) + ".html",
+						Name:    fullName,
+						SortKey: strings.ToLower(c.LastName + " " + c.FirstName),
+						Club:    club,
+						Acronym: acronym,
+					}
+					athletes[key] = athlete
+				}
+				athlete.Results = append(athlete.Results, siteAthleteResult{Event: event, Place: c.Place, Time: c.Time})
+				row.AthletePage = athlete.Page
+			}
+			event.Rows = append(event.Rows, row)
+		}
+		events = append(events, event)
+	}
+	athleteList := make([]*siteAthlete, 0, len(athletes))
+	for _, athlete := range athletes {
+		athleteList = append(athleteList, athlete)
+	}
+	sort.Slice(athleteList, func(i, j int) bool {
+		if athleteList[i].SortKey != athleteList[j].SortKey {
+			return athleteList[i].SortKey < athleteList[j].SortKey
+		}
+		return athleteList[i].Page < athleteList[j].Page
+	})
+	var shownSessions []*siteSession
+	for _, session := range indexSessions {
+		if len(session.Events) > 0 {
+			shownSessions = append(shownSessions, session)
+		}
+	}
+
+	title := meet.Name
+	if title == "" {
+		title = "Results"
+	}
+	common := map[string]interface{}{
+		"Meet":      meet,
+		"Title":     title,
+		"Generated": now,
+		"Version":   appVersion,
+	}
+	page := func(name, root string, data map[string]interface{}) (sitePage, error) {
+		values := map[string]interface{}{"Root": root}
+		for k, v := range common {
+			values[k] = v
+		}
+		for k, v := range data {
+			values[k] = v
+		}
+		var out bytes.Buffer
+		if err := siteTemplates.ExecuteTemplate(&out, strings.SplitN(name, "/", 2)[0], values); err != nil {
+			return sitePage{}, fmt.Errorf("failed to render %s: %v", name, err)
+		}
+		return sitePage{Name: name, Content: out.Bytes()}, nil
+	}
+
+	pages := []sitePage{{Name: "style.css", Content: []byte(siteStyle)}}
+	add := func(p sitePage, err error) error {
+		if err != nil {
+			return err
+		}
+		pages = append(pages, p)
+		return nil
+	}
+	if err := add(page("index.html", "", map[string]interface{}{"Sessions": shownSessions, "Results": len(events)})); err != nil {
+		return nil, err
+	}
+	if err := add(page("athletes.html", "", map[string]interface{}{"Athletes": athleteList})); err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if err := add(page(event.Page, "../", map[string]interface{}{"Event": event})); err != nil {
+			return nil, err
+		}
+	}
+	for _, athlete := range athleteList {
+		if err := add(page(athlete.Page, "../", map[string]interface{}{"Athlete": athlete})); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// renderSite renders the current results as a static HTML site.
+func (a *App) renderSite() ([]sitePage, error) {
+	results, err := a.GetAllLIFData()
+	if err != nil {
+		return nil, err
+	}
+	return buildSite(results, a.GetMeet(), a.clubAcronyms(), time.Now())
+}
+
+// siteManifest lists the files of an export, so the next export into the same
+// folder can remove the pages of events and athletes that are gone.
+const siteManifest = "polyfield-site.json"
+
+// writeSite writes a rendered site into dir, removing the pages of an earlier
+// export that aren't in this one. Only files listed in the earlier export's
+// manifest are removed; anything else in dir is left alone.
+func writeSite(dir string, pages []sitePage) error {
+	var previous []string
+	if raw, err := os.ReadFile(filepath.Join(dir, siteManifest)); err == nil {
+		if err := json.Unmarshal(raw, &previous); err != nil {
+			return fmt.Errorf("failed to read %s: %v", siteManifest, err)
+		}
+	}
+	written := make(map[string]bool, len(pages))
+	names := make([]string, 0, len(pages))
+	for _, p := range pages {
+		path := filepath.Join(dir, filepath.FromSlash(p.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, p.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", p.Name, err)
+		}
+		written[p.Name] = true
+		names = append(names, p.Name)
+	}
+	for _, name := range previous {
+		if written[name] || !filepath.IsLocal(filepath.FromSlash(name)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", name, err)
+		}
+	}
+	raw, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, siteManifest), raw, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", siteManifest, err)
+	}
+	return nil
+}
+
+// saveSite renders the current results and writes them to dir as a static site.
+func (a *App) saveSite(dir string) (int, error) {
+	pages, err := a.renderSite()
+	if err != nil {
+		return 0, err
+	}
+	if err := writeSite(dir, pages); err != nil {
+		return 0, err
+	}
+	resultsLog.Info("Results site exported", "dir", dir, "files", len(pages))
+	return len(pages), nil
+}
+
+// ExportSite asks for a folder and writes the results into it as a static HTML
+// site. It returns the folder, or an empty string if cancelled.
+func (a *App) ExportSite() (string, error) {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Export Results Site",
+		CanCreateDirectories: true,
+	})
+	if err != nil || dir == "" {
+		return "", err
+	}
+	if _, err := a.saveSite(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// newCommandApp returns an App for a command that reads a results directory, or
+// a meet archive if source is one, without starting the server or the watcher.
+func newCommandApp(source string) (*App, error) {
+	logLevel.Set(slog.LevelWarn)
+	app := NewApp()
+	if strings.HasSuffix(strings.ToLower(source), archiveExtension) {
+		if _, err := app.loadArchive(source); err != nil {
+			return nil, err
+		}
+		return app, nil
+	}
+	dir, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	app.loadMediaIndex()
+	app.monitoredDir = dir
+	// Hold mode as the app has it for this directory, so that pending and rejected
+	// results stay unpublished.
+	if path, err := settingsPath(); err == nil {
+		if settings, err := readSettings(path); err == nil && settings != nil && filepath.Clean(settings.MonitoredDir) == dir {
+			app.holdForApproval = settings.HoldForApproval
+		}
+	}
+	app.loadMeetSettings(dir)
+	app.initOverrides()
+	app.initApprovals()
+	if acronyms, err := loadClubListCSV(dir); err == nil {
+		app.customClubAcronyms = acronyms
+	}
+	return app, nil
+}
+
+// runSite exports a results directory or meet archive as a static HTML site from
+// the command line.
+func runSite(args []string) error {
+	flags := flag.NewFlagSet("site", flag.ContinueOnError)
+	output := flags.String("o", "results-site", "folder to write the site to")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s site [-o folder] results-directory|meet%s\n", filepath.Base(os.Args[0]), archiveExtension)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("give one results directory or meet archive")
+	}
+	app, err := newCommandApp(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	files, err := app.saveSite(*output)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d files to %s\n", files, *output)
+	return nil
+}
+
+// registerSiteRoutes adds the results site export endpoint to the Fiber server.
+func registerSiteRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/site", app.requireRole(roleAdmin), func(c *fiber.Ctx) error {
+		pages, err := app.renderSite()
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		c.Set("Content-Type", "application/zip")
+		c.Set("Content-Disposition", `attachment; filename="results-site.zip"`)
+		archive := zip.NewWriter(c.Response().BodyWriter())
+		for _, p := range pages {
+			w, err := archive.Create(p.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(p.Content); err != nil {
+				return err
+			}
+		}
+		return archive.Close()
+	})
+}
+
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("Mon 2 Jan 2006 15:04") },
+	"time": func(t time.Time) string { return t.Format("15:04") },
+	// page adds the page title to a page's values, for the header.
+	"page": func(values map[string]interface{}, title string) map[string]interface{} {
+		withTitle := map[string]interface{}{"Page": title}
+		for k, v := range values {
+			withTitle[k] = v
+		}
+		return withTitle
+	},
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Page}} - {{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header>
+<h1><a href="{{.Root}}index.html">{{.Title}}</a></h1>
+{{with .Meet}}{{if or .Venue .StartDate}}<p>{{.Venue}}{{if and .Venue .StartDate}}, {{end}}{{.StartDate}}{{if and .EndDate (ne .EndDate .StartDate)}} to {{.EndDate}}{{end}}</p>{{end}}{{end}}
+<nav><a href="{{.Root}}index.html">Events</a> <a href="{{.Root}}athletes.html">Athletes</a></nav>
+</header>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+<footer>Generated {{date .Generated}} by PolyField Track {{.Version}}</footer>
+</body>
+</html>
+{{end}}
+
+{{define "index.html"}}{{template "header" (page . "Events")}}
+{{if not .Sessions}}<p>No results yet.</p>{{end}}
+{{range .Sessions}}<section>
+<h2>{{.Name}}</h2>
+{{range .Events}}<h3>{{.Name}}</h3>
+<table>
+<thead><tr><th>Time</th><th>Event</th><th>Winner</th><th>Wind</th></tr></thead>
+<tbody>
+{{range .Events}}<tr><td>{{time .Saved}}</td><td><a href="{{.Page}}">{{.Data.EventName}}</a></td><td>{{range .Rows}}{{if eq .Place "1"}}{{.Name}} {{.Time}} {{end}}{{end}}</td><td>{{.Data.Wind}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}</section>
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "athletes.html"}}{{template "header" (page . "Athletes")}}
+<h2>Athletes</h2>
+{{if not .Athletes}}<p>No athletes yet.</p>{{end}}
+<ul class="athletes">
+{{range .Athletes}}<li><a href="{{.Page}}">{{.Name}}</a>{{with .Club}} <span class="club">{{.}}</span>{{end}}</li>
+{{end}}</ul>
+{{template "footer" .}}{{end}}
+
+{{define "events"}}{{with .Event}}{{template "header" (page $ .Data.EventName)}}
+<h2>{{.Data.EventName}}</h2>
+<p class="details">{{if .Session}}{{.Session}} &middot; {{time .Saved}}{{else}}{{date .Saved}}{{end}}{{with .Data.Wind}} &middot; Wind {{.}}{{end}}{{if .Data.Amended}} &middot; <strong>Amended</strong>{{end}}</p>
+<table>
+<thead><tr><th>Place</th><th>Bib</th><th>Name</th><th>Club</th><th>Time</th></tr></thead>
+<tbody>
+{{range .Rows}}<tr><td>{{.Place}}</td><td>{{.ID}}</td><td>{{if .AthletePage}}<a href="{{$.Root}}{{.AthletePage}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td title="{{.Affiliation}}">{{.Club}}</td><td>{{.Time}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "athletes"}}{{with .Athlete}}{{template "header" (page $ .Name)}}
+<h2>{{.Name}}</h2>
+{{if .Club}}<p class="details">{{.Club}}{{with .Acronym}} ({{.}}){{end}}</p>{{end}}
+<table>
+<thead><tr><th>Event</th><th>Session</th><th>Place</th><th>Time</th><th>Wind</th></tr></thead>
+<tbody>
+{{range .Results}}<tr><td><a href="{{$.Root}}{{.Event.Page}}">{{.Event.Data.EventName}}</a></td><td>{{.Event.Session}}</td><td>{{.Place}}</td><td>{{.Time}}</td><td>{{.Event.Data.Wind}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}{{template "footer" .}}{{end}}
+`))
+
+const siteStyle = `body { font-family: system-ui, -apple-system, "Segoe UI", Roboto, sans-serif; margin: 0; color: #1a1a1a; background: #f5f7fa; }
+header { background: #0a1628; color: #ffffff; padding: 16px 24px; }
+header h1 { margin: 0 0 4px; font-size: 1.5rem; }
+header a { color: #ffffff; text-decoration: none; }
+header p { margin: 0 0 8px; color: #a0b4c8; }
+nav a { margin-right: 16px; color: #FFD700; }
+main { max-width: 960px; margin: 0 auto; padding: 16px 24px; }
+h2 { margin-top: 24px; }
+h3 { margin: 16px 0 6px; font-size: 1.05rem; }
+.details { color: #555555; }
+table { width: 100%; border-collapse: collapse; background: #ffffff; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid #dde3ea; }
+th { background: #1565c0; color: #ffffff; }
+tbody tr:nth-child(even) { background: #f0f4f8; }
+a { color: #1565c0; }
+.athletes { columns: 2; padding-left: 20px; }
+.club { color: #555555; font-size: 0.9em; }
+footer { max-width: 960px; margin: 0 auto; padding: 16px 24px; color: #777777; font-size: 0.85rem; }
+`
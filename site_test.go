@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventGroupName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"U17 Women 100m Heat 2", "U17 Women 100m"},
+		{"U17 Women 100m Heat 12", "U17 Women 100m"},
+		{"U17 Women 100m Final", "U17 Women 100m"},
+		{"Men 400m Semi-Final 1", "Men 400m"},
+		{"Men 400m Round 1 Heat 3", "Men 400m"},
+		{"U15 Boys 1500m Final A", "U15 Boys 1500m"},
+		{"Final Women 200m", "Women 200m"},
+		{"Mixed 4x400m Relay", "Mixed 4x400m Relay"},
+		{"Final", "Final"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventGroupName(tt.name); got != tt.want {
+				t.Errorf("eventGroupName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSite(t *testing.T) {
+	saved := func(value string) int64 {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.Unix()
+	}
+	alice := Competitor{Place: "1", ID: "101", FirstName: "Alice", LastName: "Smith", Affiliation: "Kingston", Time: "12.01"}
+	bella := Competitor{Place: "1", ID: "102", FirstName: "Bella", LastName: "Jones", Affiliation: "Sutton", Time: "12.20"}
+	results := []*LifData{
+		{FileName: "002-1-01.lif", EventName: "Women 200m Heat 1", ModifiedTime: saved("2026-06-13 10:20"), Competitors: []Competitor{bella}},
+		{FileName: "001-1-01.lif", EventName: "Women 100m Heat 1", ModifiedTime: saved("2026-06-13 10:00"), Competitors: []Competitor{alice}},
+		{FileName: "001-1-02.lif", EventName: "Women 100m Heat 2", ModifiedTime: saved("2026-06-13 10:05"), Competitors: []Competitor{bella}},
+		{FileName: "001-2-01.lif", EventName: "Women 100m Final", ModifiedTime: saved("2026-06-13 11:00"), Competitors: []Competitor{alice, {Place: "2", ID: "102", FirstName: "Bella", LastName: "Jones", Affiliation: "Sutton", Time: "12.15"}}},
+	}
+	meet := Meet{Name: "County Champs", Sessions: []MeetSession{{Name: "Saturday", Date: "2026-06-13", Start: "09:00"}}}
+	pages, err := buildSite(results, meet, map[string]string{"kingston": "KAC"}, time.Unix(saved("2026-06-13 18:00"), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := make(map[string]string)
+	var names []string
+	for _, p := range pages {
+		content[p.Name] = string(p.Content)
+		names = append(names, p.Name)
+	}
+	want := []string{
+		"style.css", "index.html", "athletes.html",
+		"events/001-1-01.html", "events/001-1-02.html", "events/002-1-01.html", "events/001-2-01.html",
+		"athletes/bella-jones-sutton.html", "athletes/alice-smith-kingston.html",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("pages = %v, want %v", names, want)
+	}
+
+	// The heats and final of the 100m are listed together, before the 200m.
+	index := content["index.html"]
+	order := []string{"<h2>Saturday</h2>", "<h3>Women 100m</h3>", "events/001-1-01.html", "events/001-1-02.html", "events/001-2-01.html", "<h3>Women 200m</h3>", "events/002-1-01.html"}
+	last := -1
+	for _, s := range order {
+		i := strings.Index(index, s)
+		if i < last {
+			t.Errorf("index.html has %q out of order", s)
+		}
+		last = i
+	}
+	if strings.Count(index, "<h3>") != 2 {
+		t.Errorf("index.html has %d events, want 2", strings.Count(index, "<h3>"))
+	}
+	if !strings.Contains(content["events/001-2-01.html"], `<td title="Kingston">KAC</td>`) {
+		t.Error("event page doesn't show the club acronym")
+	}
+	if strings.Count(content["athletes/alice-smith-kingston.html"], "<tr><td>") != 2 {
+		t.Error("athlete page doesn't list both of Alice's results")
+	}
+}
+
+func TestWriteSite(t *testing.T) {
+	dir := t.TempDir()
+	// Someone else's page in the same folder.
+	if err := os.MkdirAll(filepath.Join(dir, "events"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "events", "other.html")
+	if err := os.WriteFile(other, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := []sitePage{{Name: "index.html"}, {Name: "events/100m.html"}, {Name: "events/200m.html"}}
+	if err := writeSite(dir, first); err != nil {
+		t.Fatal(err)
+	}
+	second := []sitePage{{Name: "index.html"}, {Name: "events/100m.html"}}
+	if err := writeSite(dir, second); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"index.html": true, "events/100m.html": true, "events/200m.html": false, "events/other.html": true} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		if got := err == nil; got != want {
+			t.Errorf("%s exists = %v, want %v", name, got, want)
+		}
+	}
+
+	// A manifest naming files outside the folder removes nothing.
+	outside := filepath.Join(t.TempDir(), "keep.html")
+	os.WriteFile(outside, []byte("keep"), 0644)
+	os.WriteFile(filepath.Join(dir, siteManifest), []byte(`["../`+filepath.Base(filepath.Dir(outside))+`/keep.html", "`+outside+`"]`), 0644)
+	if err := writeSite(dir, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the site was removed: %v", err)
+	}
+}
+
+func TestNewCommandAppHonoursApprovals(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,12.01")
+	app := newTestApp(t, dir, true)
+	saveResult(t, app, writeLif(t, dir, "a.lif", "100m", "1,101,4,Smith,Alice,Kingston,11.50"))
+	saveResult(t, app, writeLif(t, dir, "b.lif", "200m", "1,102,3,Jones,Bella,Sutton,25.10"))
+	if err := app.RejectResult(app.GetPendingResults()[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	writeLif(t, dir, "c.lif", "400m", "1,103,5,Brown,Cara,Sutton,58.00")
+
+	// Hold mode off: the rejected and the pending saves aren't published.
+	command, err := newCommandApp(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := publishedTimes(t, command); got["a.lif"] != "12.01" || got["c.lif"] != "58.00" || len(got) != 2 {
+		t.Errorf("published with hold off = %v, want the approved a.lif and c.lif", got)
+	}
+
+	// Hold mode on in the meet settings: c.lif hasn't been approved either.
+	if err := os.WriteFile(filepath.Join(dir, meetSettingsFile), []byte(`{"holdForApproval": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	command, err = newCommandApp(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := publishedTimes(t, command); got["a.lif"] != "12.01" || len(got) != 1 {
+		t.Errorf("published with hold on = %v, want only the approved a.lif", got)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// httpsPort is the port HTTPS is served on, next to plain HTTP. Like HTTP, it
+	// falls back to the next free port if it is taken.
+	httpsPort = 3443
+	// serverCertLifetime is kept under the 398 days browsers accept for server certificates.
+	serverCertLifetime = 397 * 24 * time.Hour
+	// caCertLifetime is long enough that tablets only need to trust the CA once.
+	caCertLifetime = 10 * 365 * 24 * time.Hour
+	// certRenewBefore is how close to expiry the server certificate is replaced.
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+// tlsDir returns the directory the local CA and server certificate are stored in.
+func tlsDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "PolyField-Track", "tls"), nil
+}
+
+// writePEM writes a single PEM block to path.
+func writePEM(path string, blockType string, der []byte, perm os.FileMode) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("failed to save %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// serialNumber returns a random certificate serial number.
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// loadOrCreateCA loads the local certificate authority from dir, creating it the first time.
+func loadOrCreateCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPath := filepath.Join(dir, "ca.pem")
+	keyPath := filepath.Join(dir, "ca-key.pem")
+	if pair, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil {
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+		if err == nil && ok && time.Now().Before(cert.NotAfter) {
+			return cert, key, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "PolyField Track Local CA", Organization: []string{"PolyField"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caCertLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode CA key: %v", err)
+	}
+	if err := writePEM(keyPath, "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(certPath, "CERTIFICATE", der, 0644); err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	httpsLog.Info("Created local certificate authority", "dir", dir)
+	return cert, key, nil
+}
+
+// certCovers reports whether cert is valid for a while yet and covers every hostname and IP.
+func certCovers(cert *x509.Certificate, hostnames []string, ips []net.IP) bool {
+	if time.Now().Add(certRenewBefore).After(cert.NotAfter) {
+		return false
+	}
+	for _, hostname := range hostnames {
+		if cert.VerifyHostname(hostname) != nil {
+			return false
+		}
+	}
+	for _, ip := range ips {
+		if cert.VerifyHostname(ip.String()) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureCertificates returns the server certificate for the mDNS hostname, creating
+// the local CA and issuing a new server certificate when it is missing, about to
+// expire, or doesn't cover the hostname and the machine's current LAN addresses
+// (e.g. at a new venue).
+func ensureCertificates(hostname string) (*tls.Certificate, error) {
+	dir, err := tlsDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create certificate directory: %v", err)
+	}
+	certPath := filepath.Join(dir, "server.pem")
+	keyPath := filepath.Join(dir, "server-key.pem")
+	hostnames := []string{hostname, "localhost"}
+	ips := append(getLANIPs(), net.IPv4(127, 0, 0, 1))
+
+	if pair, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil {
+		if cert, err := x509.ParseCertificate(pair.Certificate[0]); err == nil && certCovers(cert, hostnames, ips) {
+			return &pair, nil
+		}
+	}
+
+	caCert, caKey, err := loadOrCreateCA(dir)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate server key: %v", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hostname, Organization: []string{"PolyField"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(serverCertLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     hostnames,
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode server key: %v", err)
+	}
+	if err := writePEM(keyPath, "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return nil, err
+	}
+	if err := writePEM(certPath, "CERTIFICATE", der, 0644); err != nil {
+		return nil, err
+	}
+	httpsLog.Info("Issued server certificate", "hostnames", hostnames, "ips", ips)
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return &pair, nil
+}
+
+// startHTTPS serves the Fiber app over HTTPS. Errors are logged rather than
+// fatal, so a certificate problem leaves plain HTTP running.
+func (a *App) startHTTPS() {
+	a.mu.Lock()
+	server := a.server
+	running := a.httpsListener != nil
+	a.mu.Unlock()
+	if server == nil || running {
+		return
+	}
+	cert, err := ensureCertificates(a.GetMDNSSettings().Hostname)
+	if err != nil {
+		httpsLog.Error("Failed to start HTTPS", "error", err)
+		return
+	}
+	address, _ := a.serverAddress()
+	plain, port, err := listenWithFallback(address, httpsPort)
+	if err != nil {
+		httpsLog.Error("Failed to start HTTPS", "error", err)
+		return
+	}
+	ln := tls.NewListener(plain, &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+	a.mu.Lock()
+	if a.server != server {
+		// The server restarted while the certificate was being prepared.
+		a.mu.Unlock()
+		ln.Close()
+		return
+	}
+	a.httpsListener = ln
+	a.activeHTTPSPort = port
+	a.mu.Unlock()
+	httpsLog.Info("Serving HTTPS", "port", port)
+	go func() {
+		err := server.Listener(ln)
+		a.mu.Lock()
+		stopped := a.httpsListener != ln
+		a.mu.Unlock()
+		if err != nil && !stopped {
+			httpsLog.Error("HTTPS server stopped", "error", err)
+		}
+	}()
+}
+
+// stopHTTPS closes the HTTPS listener, leaving plain HTTP running.
+func (a *App) stopHTTPS() {
+	a.mu.Lock()
+	ln := a.httpsListener
+	a.httpsListener = nil
+	a.activeHTTPSPort = 0
+	a.mu.Unlock()
+	if ln != nil {
+		ln.Close()
+		httpsLog.Info("Stopped")
+	}
+}
+
+// GetHTTPSEnabled reports whether the server is also served over HTTPS.
+func (a *App) GetHTTPSEnabled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.httpsEnabled
+}
+
+// SetHTTPSEnabled turns HTTPS on or off. The local CA and server certificate are
+// created the first time it is turned on.
+func (a *App) SetHTTPSEnabled(enabled bool) error {
+	if enabled {
+		if _, err := ensureCertificates(a.GetMDNSSettings().Hostname); err != nil {
+			return err
+		}
+	}
+	a.mu.Lock()
+	a.httpsEnabled = enabled
+	a.mu.Unlock()
+	if enabled {
+		a.startHTTPS()
+	} else {
+		a.stopHTTPS()
+	}
+	a.scheduleSave()
+	return nil
+}
+
+// GetCACertificatePath returns the path of the local CA certificate, for installing on tablets.
+func (a *App) GetCACertificatePath() (string, error) {
+	dir, err := tlsDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "ca.pem")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no certificate authority yet, turn on HTTPS first")
+	}
+	return path, nil
+}
+
+// registerTLSRoutes adds the CA certificate download to the Fiber server.
+func registerTLSRoutes(fiberApp *fiber.App, app *App) {
+	fiberApp.Get("/ca.crt", func(c *fiber.Ctx) error {
+		path, err := app.GetCACertificatePath()
+		if err != nil {
+			return c.Status(404).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return c.Status(500).JSON(map[string]interface{}{"error": err.Error()})
+		}
+		c.Set("Content-Type", "application/x-x509-ca-cert")
+		c.Set("Content-Disposition", `attachment; filename="polyfield-ca.crt"`)
+		return c.Send(data)
+	})
+}